@@ -0,0 +1,70 @@
+package geminisdk
+
+import "sync"
+
+// Agent bundles a reusable persona on top of Session: a name, a system
+// prompt, a bound ToolRegistry, and optional default generation/thinking
+// configuration. Tools registered on an Agent's registry are only exposed
+// to sessions created for that agent, not to the SDK globally.
+type Agent struct {
+	Name             string
+	SystemPrompt     string
+	Tools            *ToolRegistry
+	GenerationConfig *GenerationConfig
+	ThinkingConfig   *ThinkingConfig
+}
+
+// NewAgent creates an Agent with its own empty ToolRegistry.
+func NewAgent(name, systemPrompt string) *Agent {
+	return &Agent{
+		Name:         name,
+		SystemPrompt: systemPrompt,
+		Tools:        NewToolRegistry(),
+	}
+}
+
+// AgentRegistry stores reusable named agents (e.g. "coder", "researcher")
+// so callers can define them once and switch between them by name.
+type AgentRegistry struct {
+	mu     sync.RWMutex
+	agents map[string]*Agent
+}
+
+// NewAgentRegistry creates an empty agent registry.
+func NewAgentRegistry() *AgentRegistry {
+	return &AgentRegistry{agents: make(map[string]*Agent)}
+}
+
+// Register adds or replaces an agent under its Name.
+func (r *AgentRegistry) Register(agent *Agent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.agents[agent.Name] = agent
+}
+
+// Get returns the agent registered under name, if any.
+func (r *AgentRegistry) Get(name string) (*Agent, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	agent, ok := r.agents[name]
+	return agent, ok
+}
+
+// Unregister removes an agent.
+func (r *AgentRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.agents, name)
+}
+
+// Names returns the names of all registered agents.
+func (r *AgentRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.agents))
+	for name := range r.agents {
+		names = append(names, name)
+	}
+	return names
+}
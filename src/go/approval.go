@@ -0,0 +1,45 @@
+package geminisdk
+
+import "context"
+
+type approvalAction int
+
+const (
+	approvalApprove approvalAction = iota
+	approvalReject
+	approvalModify
+)
+
+// ApprovalDecision is returned by an ApprovalPolicy to control whether a
+// pending tool call executes, is rejected, or runs with substituted
+// arguments. Construct one with Approve, Reject, or ModifyArguments.
+type ApprovalDecision struct {
+	action approvalAction
+	reason string
+	args   map[string]interface{}
+}
+
+// Approve allows the pending tool call to proceed unmodified.
+func Approve() ApprovalDecision {
+	return ApprovalDecision{action: approvalApprove}
+}
+
+// Reject rejects the pending tool call with reason. The handler is never
+// invoked; a RejectedResult carrying reason is appended to the transcript
+// in its place.
+func Reject(reason string) ApprovalDecision {
+	return ApprovalDecision{action: approvalReject, reason: reason}
+}
+
+// ModifyArguments approves the pending tool call but substitutes newArgs
+// for the arguments the model supplied before the handler is invoked.
+func ModifyArguments(newArgs map[string]interface{}) ApprovalDecision {
+	return ApprovalDecision{action: approvalModify, args: newArgs}
+}
+
+// ApprovalPolicy decides what happens to a tool call before its handler is
+// invoked. A Session with no policy configured auto-approves every call,
+// preserving the default execute-immediately behavior; set SessionConfig's
+// ApprovalPolicy (or call Session.SetApprovalPolicy) to require explicit
+// confirmation, e.g. from a TUI prompting the user after EventToolCallPending.
+type ApprovalPolicy func(ctx context.Context, invocation ToolInvocation) ApprovalDecision
@@ -3,6 +3,7 @@ package geminisdk
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,8 +13,23 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
+// OAuthManagerOption configures an OAuthManager at construction time.
+type OAuthManagerOption func(*OAuthManager)
+
+// WithCredentialStore overrides the default FileCredentialStore used to
+// persist OAuth credentials. Use this to switch to EnvCredentialStore,
+// MemoryCredentialStore, OSKeyringCredentialStore, or a custom
+// CredentialStore implementation.
+func WithCredentialStore(store CredentialStore) OAuthManagerOption {
+	return func(m *OAuthManager) {
+		m.store = store
+	}
+}
+
 // OAuthManager handles OAuth authentication for Gemini CLI
 type OAuthManager struct {
 	oauthPath    string
@@ -21,12 +37,16 @@ type OAuthManager struct {
 	clientSecret string
 	credentials  *GeminiOAuthCredentials
 	projectID    string
+	store        CredentialStore
 	httpClient   *http.Client
+	refreshGroup singleflight.Group
 	mu           sync.RWMutex
 }
 
-// NewOAuthManager creates a new OAuth manager
-func NewOAuthManager(oauthPath, clientID, clientSecret string) *OAuthManager {
+// NewOAuthManager creates a new OAuth manager. By default credentials are
+// persisted with a FileCredentialStore rooted at oauthPath; pass
+// WithCredentialStore to use a different backend.
+func NewOAuthManager(oauthPath, clientID, clientSecret string, opts ...OAuthManagerOption) *OAuthManager {
 	if clientID == "" {
 		clientID = GeminiOAuthClientID
 	}
@@ -34,14 +54,21 @@ func NewOAuthManager(oauthPath, clientID, clientSecret string) *OAuthManager {
 		clientSecret = GeminiOAuthClientSecret
 	}
 
-	return &OAuthManager{
+	m := &OAuthManager{
 		oauthPath:    oauthPath,
 		clientID:     clientID,
 		clientSecret: clientSecret,
+		store:        NewFileCredentialStore(oauthPath),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
 }
 
 func (m *OAuthManager) getCredentialPath() string {
@@ -49,33 +76,11 @@ func (m *OAuthManager) getCredentialPath() string {
 }
 
 func (m *OAuthManager) loadCachedCredentials() (*GeminiOAuthCredentials, error) {
-	keyFile := m.getCredentialPath()
-
-	data, err := os.ReadFile(keyFile)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, NewCredentialsNotFoundError(keyFile)
-		}
-		return nil, err
-	}
-
-	var creds GeminiOAuthCredentials
-	if err := json.Unmarshal(data, &creds); err != nil {
-		return nil, err
-	}
-
-	return &creds, nil
+	return m.store.Load(context.Background())
 }
 
 func (m *OAuthManager) saveCredentials(creds *GeminiOAuthCredentials) error {
-	keyFile := m.getCredentialPath()
-
-	data, err := json.MarshalIndent(creds, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(keyFile, data, 0600)
+	return m.store.Save(context.Background(), creds)
 }
 
 func (m *OAuthManager) refreshAccessToken(creds *GeminiOAuthCredentials) (*GeminiOAuthCredentials, error) {
@@ -102,7 +107,9 @@ func (m *OAuthManager) refreshAccessToken(creds *GeminiOAuthCredentials) (*Gemin
 
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		refreshErr := NewTokenRefreshError(fmt.Sprintf("Token refresh request failed: %v", err), 0, "")
+		refreshErr.Cause = err
+		return nil, refreshErr
 	}
 	defer resp.Body.Close()
 
@@ -180,28 +187,45 @@ func (m *OAuthManager) InvalidateCredentials() {
 	m.credentials = nil
 }
 
-// EnsureAuthenticated ensures we have a valid access token
+// EnsureAuthenticated ensures we have a valid access token. Concurrent
+// callers racing on a refresh are coalesced through a singleflight.Group
+// keyed on the refresh token, so a burst of sessions waking at once doesn't
+// hammer the token endpoint with duplicate requests.
 func (m *OAuthManager) EnsureAuthenticated(forceRefresh bool) (string, error) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	if m.credentials == nil {
 		creds, err := m.loadCachedCredentials()
 		if err != nil {
+			m.mu.Unlock()
 			return "", err
 		}
 		m.credentials = creds
 	}
 
-	if forceRefresh || !m.isTokenValid(m.credentials) {
-		newCreds, err := m.refreshAccessToken(m.credentials)
-		if err != nil {
-			return "", err
-		}
-		m.credentials = newCreds
+	if !forceRefresh && m.isTokenValid(m.credentials) {
+		token := m.credentials.AccessToken
+		m.mu.Unlock()
+		return token, nil
+	}
+
+	creds := m.credentials
+	m.mu.Unlock()
+
+	v, err, _ := m.refreshGroup.Do(creds.RefreshToken, func() (interface{}, error) {
+		return m.refreshAccessToken(creds)
+	})
+	if err != nil {
+		return "", err
 	}
 
-	return m.credentials.AccessToken, nil
+	newCreds := v.(*GeminiOAuthCredentials)
+
+	m.mu.Lock()
+	m.credentials = newCreds
+	m.mu.Unlock()
+
+	return newCreds.AccessToken, nil
 }
 
 // GetCredentials returns current credentials
@@ -214,6 +238,14 @@ func (m *OAuthManager) GetCredentials() (*GeminiOAuthCredentials, error) {
 	return m.credentials, nil
 }
 
+// peekCredentials returns the currently cached credentials without
+// triggering a load or refresh, or nil if none are cached yet.
+func (m *OAuthManager) peekCredentials() *GeminiOAuthCredentials {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.credentials
+}
+
 // GetAPIEndpoint returns the API endpoint URL
 func (m *OAuthManager) GetAPIEndpoint() string {
 	return fmt.Sprintf("%s/%s", GeminiCodeAssistEndpoint, GeminiCodeAssistAPIVersion)
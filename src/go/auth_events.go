@@ -0,0 +1,68 @@
+package geminisdk
+
+import (
+	"math/rand"
+	"time"
+)
+
+// AuthEvent is implemented by every event emitted on Client.AuthEvents(),
+// giving callers a programmatic way to learn when their credentials were
+// refreshed, failed to refresh, or became unrecoverably invalid.
+type AuthEvent interface {
+	isAuthEvent()
+}
+
+// RefreshedEvent is emitted after a successful token refresh.
+type RefreshedEvent struct {
+	ExpiresAt time.Time
+}
+
+func (RefreshedEvent) isAuthEvent() {}
+
+// RefreshFailedEvent is emitted when a refresh attempt fails but will be
+// retried at NextAttempt.
+type RefreshFailedEvent struct {
+	Err         error
+	NextAttempt time.Time
+}
+
+func (RefreshFailedEvent) isAuthEvent() {}
+
+// AuthInvalidatedEvent is emitted when credentials are found to be
+// unrecoverably invalid (e.g. a revoked or missing refresh token) and the
+// scheduler has given up retrying without user intervention.
+type AuthInvalidatedEvent struct{}
+
+func (AuthInvalidatedEvent) isAuthEvent() {}
+
+// fullJitterBackoff implements capped exponential backoff with full jitter:
+// sleep = rand(0, min(cap, base*2^attempt)).
+type fullJitterBackoff struct {
+	base    time.Duration
+	cap     time.Duration
+	attempt int
+}
+
+func newFullJitterBackoff(base, cap time.Duration) *fullJitterBackoff {
+	return &fullJitterBackoff{base: base, cap: cap}
+}
+
+func (b *fullJitterBackoff) next() time.Duration {
+	b.attempt++
+
+	shift := uint(b.attempt)
+	if shift > 30 {
+		shift = 30
+	}
+
+	ceiling := b.base * time.Duration(int64(1)<<shift)
+	if ceiling <= 0 || ceiling > b.cap {
+		ceiling = b.cap
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+func (b *fullJitterBackoff) reset() {
+	b.attempt = 0
+}
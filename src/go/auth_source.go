@@ -0,0 +1,390 @@
+package geminisdk
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// CredentialSource produces access tokens from a specific authentication
+// mechanism, letting Client run unattended in environments such as
+// GKE/Cloud Run/CI that can't complete the installed-app OAuth dance.
+type CredentialSource interface {
+	// Token returns a valid, unexpired access token.
+	Token(ctx context.Context) (string, error)
+}
+
+// OAuthCredentialSource adapts the existing cached-OAuth refresh-token flow
+// to the CredentialSource interface. Client does not construct one itself:
+// with no CredentialSource configured it runs the same flow by calling
+// OAuthManager directly.
+type OAuthCredentialSource struct {
+	manager *OAuthManager
+}
+
+// NewOAuthCredentialSource wraps manager as a CredentialSource.
+func NewOAuthCredentialSource(manager *OAuthManager) *OAuthCredentialSource {
+	return &OAuthCredentialSource{manager: manager}
+}
+
+func (s *OAuthCredentialSource) Token(ctx context.Context) (string, error) {
+	return s.manager.EnsureAuthenticated(false)
+}
+
+// serviceAccountKey is the subset of a Google service-account JSON key file
+// needed to sign a JWT assertion.
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// ServiceAccountCredentialSource authenticates as a service account by
+// signing a JWT with its private key and exchanging it at Google's token
+// endpoint via the urn:ietf:params:oauth:grant-type:jwt-bearer flow.
+type ServiceAccountCredentialSource struct {
+	key        serviceAccountKey
+	scopes     []string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewServiceAccountCredentialSource parses a service-account JSON key and
+// returns a source that mints access tokens for the given scopes. An empty
+// scopes slice defaults to GeminiOAuthScopes.
+func NewServiceAccountCredentialSource(keyJSON []byte, scopes []string) (*ServiceAccountCredentialSource, error) {
+	var key serviceAccountKey
+	if err := json.Unmarshal(keyJSON, &key); err != nil {
+		return nil, err
+	}
+	if key.PrivateKey == "" || key.ClientEmail == "" {
+		return nil, NewConfigurationError("service account key is missing private_key or client_email")
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	if len(scopes) == 0 {
+		scopes = GeminiOAuthScopes
+	}
+
+	return &ServiceAccountCredentialSource{
+		key:        key,
+		scopes:     scopes,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *ServiceAccountCredentialSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := s.signAssertion()
+	if err != nil {
+		return "", err
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	data.Set("assertion", assertion)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.key.TokenURI, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		refreshErr := NewTokenRefreshError(fmt.Sprintf("service account token exchange failed: %v", err), 0, "")
+		refreshErr.Cause = err
+		return "", refreshErr
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", NewTokenRefreshError(
+			fmt.Sprintf("service account token exchange failed: %d", resp.StatusCode),
+			resp.StatusCode,
+			string(body),
+		)
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", err
+	}
+
+	expiresIn := tokenResp.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = 3600
+	}
+
+	s.accessToken = tokenResp.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(expiresIn)*time.Second - TokenRefreshBufferMs*time.Millisecond)
+	return s.accessToken, nil
+}
+
+// signAssertion builds and signs an RS256 JWT asserting this service
+// account as the issuer, per Google's OAuth2 service account flow.
+func (s *ServiceAccountCredentialSource) signAssertion() (string, error) {
+	block, _ := pem.Decode([]byte(s.key.PrivateKey))
+	if block == nil {
+		return "", NewAuthenticationError("invalid service account private key: not PEM encoded")
+	}
+
+	parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return "", NewAuthenticationError(fmt.Sprintf("invalid service account private key: %v", err))
+	}
+
+	rsaKey, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return "", NewAuthenticationError("service account private key is not RSA")
+	}
+
+	now := time.Now()
+	header := map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": s.key.PrivateKeyID,
+	}
+	claims := map[string]interface{}{
+		"iss":   s.key.ClientEmail,
+		"scope": strings.Join(s.scopes, " "),
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+
+	headerJSON, _ := json.Marshal(header)
+	claimsJSON, _ := json.Marshal(claims)
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// ADCCredentialSource delegates to Application Default Credentials, letting
+// the SDK authenticate the same way as gcloud and other Google client
+// libraries inside GKE/Cloud Run/Compute Engine or a local `gcloud auth
+// application-default login` session.
+type ADCCredentialSource struct {
+	scopes []string
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// NewADCCredentialSource creates a source backed by
+// google.FindDefaultCredentials. An empty scopes slice defaults to
+// GeminiOAuthScopes.
+func NewADCCredentialSource(scopes []string) *ADCCredentialSource {
+	if len(scopes) == 0 {
+		scopes = GeminiOAuthScopes
+	}
+	return &ADCCredentialSource{scopes: scopes}
+}
+
+func (s *ADCCredentialSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && s.token.Valid() {
+		return s.token.AccessToken, nil
+	}
+
+	creds, err := google.FindDefaultCredentials(ctx, s.scopes...)
+	if err != nil {
+		return "", NewAuthenticationError(fmt.Sprintf("application default credentials: %v", err))
+	}
+
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		return "", NewTokenRefreshError(fmt.Sprintf("application default credentials token fetch failed: %v", err), 0, "")
+	}
+
+	s.token = token
+	return token.AccessToken, nil
+}
+
+// ExternalAccountOptions configures an ExternalAccountCredentialSource for
+// workload identity federation (e.g. exchanging an AWS or OIDC subject
+// token for a short-lived Google access token).
+type ExternalAccountOptions struct {
+	// Audience identifies the workload identity pool provider, e.g.
+	// "//iam.googleapis.com/projects/.../workloadIdentityPools/.../providers/...".
+	Audience string
+	// SubjectTokenType is the subject token's type, e.g.
+	// "urn:ietf:params:oauth:token-type:jwt".
+	SubjectTokenType string
+	// SubjectTokenFile reads the subject token from a local file. Mutually
+	// exclusive with SubjectTokenURL.
+	SubjectTokenFile string
+	// SubjectTokenURL fetches the subject token via an HTTP GET. Mutually
+	// exclusive with SubjectTokenFile.
+	SubjectTokenURL string
+	// TokenURL is the STS token exchange endpoint. Defaults to Google's STS.
+	TokenURL string
+	// Scopes requested for the exchanged access token. Defaults to
+	// GeminiOAuthScopes.
+	Scopes []string
+}
+
+// ExternalAccountCredentialSource exchanges a subject token (from a file or
+// URL) for a Google access token via RFC 8693 token exchange, the same
+// shape used for AWS/OIDC workload identity federation.
+type ExternalAccountCredentialSource struct {
+	opts       ExternalAccountOptions
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewExternalAccountCredentialSource creates a source from opts.
+func NewExternalAccountCredentialSource(opts ExternalAccountOptions) *ExternalAccountCredentialSource {
+	if opts.TokenURL == "" {
+		opts.TokenURL = "https://sts.googleapis.com/v1/token"
+	}
+	if len(opts.Scopes) == 0 {
+		opts.Scopes = GeminiOAuthScopes
+	}
+	return &ExternalAccountCredentialSource{
+		opts:       opts,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (s *ExternalAccountCredentialSource) readSubjectToken(ctx context.Context) (string, error) {
+	switch {
+	case s.opts.SubjectTokenFile != "":
+		data, err := os.ReadFile(s.opts.SubjectTokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+
+	case s.opts.SubjectTokenURL != "":
+		req, err := http.NewRequestWithContext(ctx, "GET", s.opts.SubjectTokenURL, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return "", NewAuthenticationError(fmt.Sprintf("subject token fetch failed: %d", resp.StatusCode))
+		}
+		return strings.TrimSpace(string(body)), nil
+
+	default:
+		return "", NewConfigurationError("ExternalAccountCredentialSource requires SubjectTokenFile or SubjectTokenURL")
+	}
+}
+
+func (s *ExternalAccountCredentialSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && time.Now().Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	subjectToken, err := s.readSubjectToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "urn:ietf:params:oauth:grant-type:token-exchange")
+	data.Set("audience", s.opts.Audience)
+	data.Set("scope", strings.Join(s.opts.Scopes, " "))
+	data.Set("requested_token_type", "urn:ietf:params:oauth:token-type:access_token")
+	data.Set("subject_token", subjectToken)
+	data.Set("subject_token_type", s.opts.SubjectTokenType)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.opts.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		refreshErr := NewTokenRefreshError(fmt.Sprintf("STS token exchange failed: %v", err), 0, "")
+		refreshErr.Cause = err
+		return "", refreshErr
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", NewTokenRefreshError(
+			fmt.Sprintf("STS token exchange failed: %d", resp.StatusCode),
+			resp.StatusCode,
+			string(body),
+		)
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", err
+	}
+
+	expiresIn := tokenResp.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = 3600
+	}
+
+	s.accessToken = tokenResp.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(expiresIn)*time.Second - TokenRefreshBufferMs*time.Millisecond)
+	return s.accessToken, nil
+}
@@ -6,9 +6,12 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,21 +24,86 @@ const (
 	onboardSleepSeconds = 2
 )
 
+const (
+	defaultMaxAttempts    = 5
+	defaultRetryBaseDelay = 1 * time.Second
+	defaultRetryCapDelay  = 30 * time.Second
+)
+
+// inlineFileSizeThreshold is the largest blob prepareMessages will inline as
+// base64 inlineData, matching Gemini's inline request size limit. Anything
+// larger must go through Backend.UploadFile and be referenced by
+// ContentPart.FileURI (fileData) instead.
+const inlineFileSizeThreshold = 20 * 1024 * 1024
+
+// resumableUploadChunkSize is the size of each Content-Range chunk
+// Backend.UploadFile PUTs to the upload session URL.
+const resumableUploadChunkSize = 8 * 1024 * 1024
+
+// RetryPolicy configures Backend's retry behavior for transient failures:
+// HTTP 429 (honoring Retry-After), 5xx, and network-level errors such as
+// connection resets or timeouts. Retries use capped exponential backoff
+// with full jitter, the same fullJitterBackoff used by the auto-refresh
+// scheduler: sleep = rand(0, min(CapDelay, BaseDelay*2^attempt)).
+//
+// 401/403 responses are treated separately: they force a credential
+// refresh and are retried immediately, without consuming a backoff delay.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first,
+	// before giving up with a RetryExhaustedError. Defaults to 5.
+	MaxAttempts int
+	// BaseDelay is the backoff base. Defaults to 1 second.
+	BaseDelay time.Duration
+	// CapDelay caps the computed backoff delay. Defaults to 30 seconds.
+	CapDelay time.Duration
+	// AttemptTimeout bounds how long a single attempt may take to receive
+	// a response (time to first byte); it does not bound reading a
+	// streaming body once the response has started. Zero disables it.
+	AttemptTimeout time.Duration
+	// OnRetry, if set, is called after each failed attempt that will be
+	// retried, so callers can observe attempts (e.g. for metrics).
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+func (p *RetryPolicy) withDefaults() *RetryPolicy {
+	out := RetryPolicy{}
+	if p != nil {
+		out = *p
+	}
+	if out.MaxAttempts <= 0 {
+		out.MaxAttempts = defaultMaxAttempts
+	}
+	if out.BaseDelay <= 0 {
+		out.BaseDelay = defaultRetryBaseDelay
+	}
+	if out.CapDelay <= 0 {
+		out.CapDelay = defaultRetryCapDelay
+	}
+	return &out
+}
+
 // BackendOptions configures the backend
 type BackendOptions struct {
-	Timeout      time.Duration
-	OAuthPath    string
-	ClientID     string
-	ClientSecret string
+	Timeout          time.Duration
+	OAuthPath        string
+	ClientID         string
+	ClientSecret     string
+	CredentialStore  CredentialStore
+	CredentialSource CredentialSource
+	// Retry configures the transient-error retry policy. Nil uses
+	// RetryPolicy's defaults (5 attempts, 1s base / 30s cap backoff).
+	Retry *RetryPolicy
 }
 
 // Backend handles API communication
 type Backend struct {
-	timeout      time.Duration
-	oauthManager *OAuthManager
-	projectID    string
-	httpClient   *http.Client
-	mu           sync.RWMutex
+	timeout          time.Duration
+	oauthManager     *OAuthManager
+	credentialSource CredentialSource
+	projectID        string
+	httpClient       *http.Client
+	retry            *RetryPolicy
+	mu               sync.RWMutex
 }
 
 // NewBackend creates a new backend
@@ -49,17 +117,51 @@ func NewBackend(opts *BackendOptions) *Backend {
 		timeout = 720 * time.Second
 	}
 
+	var oauthOpts []OAuthManagerOption
+	if opts.CredentialStore != nil {
+		oauthOpts = append(oauthOpts, WithCredentialStore(opts.CredentialStore))
+	}
+
 	return &Backend{
-		timeout:      timeout,
-		oauthManager: NewOAuthManager(opts.OAuthPath, opts.ClientID, opts.ClientSecret),
+		timeout:          timeout,
+		oauthManager:     NewOAuthManager(opts.OAuthPath, opts.ClientID, opts.ClientSecret, oauthOpts...),
+		credentialSource: opts.CredentialSource,
+		retry:            opts.Retry.withDefaults(),
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
 	}
 }
 
-func (b *Backend) getAuthHeaders(forceRefresh bool) (map[string]string, error) {
-	accessToken, err := b.oauthManager.EnsureAuthenticated(forceRefresh)
+// ChatCompletionProvider is implemented by anything a Session can send
+// messages through. *Backend (the Gemini CLI OAuth backend) is the default
+// implementation; providers/google ships a second one that talks to the
+// Generative Language REST API directly with an API key. Pass a custom
+// provider to NewClientWithProvider to swap backends without touching
+// Session or application code.
+type ChatCompletionProvider interface {
+	Complete(ctx context.Context, model string, messages []Message, generationConfig *GenerationConfig, thinkingConfig *ThinkingConfig, tools []Tool) (*LLMChunk, error)
+	CompleteStreaming(ctx context.Context, model string, messages []Message, generationConfig *GenerationConfig, thinkingConfig *ThinkingConfig, tools []Tool) (ChunkChannel, error)
+}
+
+// EmbeddingProvider is an optional capability a ChatCompletionProvider can
+// implement to support Session.Embed. *Backend implements it; a custom
+// provider that has no embeddings endpoint simply omits the method, and
+// Session.Embed reports that plainly instead of failing at the interface
+// boundary.
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, model string, inputs []EmbedInput, opts *EmbedOptions) (*EmbedResponse, error)
+}
+
+func (b *Backend) getAccessToken(ctx context.Context, forceRefresh bool) (string, error) {
+	if b.credentialSource != nil {
+		return b.credentialSource.Token(ctx)
+	}
+	return b.oauthManager.EnsureAuthenticated(forceRefresh)
+}
+
+func (b *Backend) getAuthHeaders(ctx context.Context, forceRefresh bool) (map[string]string, error) {
+	accessToken, err := b.getAccessToken(ctx, forceRefresh)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +172,7 @@ func (b *Backend) getAuthHeaders(forceRefresh bool) (map[string]string, error) {
 	}, nil
 }
 
-func (b *Backend) prepareMessages(messages []Message) []map[string]interface{} {
+func (b *Backend) prepareMessages(messages []Message) ([]map[string]interface{}, error) {
 	var result []map[string]interface{}
 
 	for _, msg := range messages {
@@ -93,15 +195,36 @@ func (b *Backend) prepareMessages(messages []Message) []map[string]interface{} {
 					"text": part.Text,
 				})
 			}
-			if len(part.ImageData) > 0 && part.ImageMimeType != "" {
-				b64Data := base64.StdEncoding.EncodeToString(part.ImageData)
+
+			if part.FileURI != "" {
 				contentParts = append(contentParts, map[string]interface{}{
-					"inlineData": map[string]interface{}{
-						"mimeType": part.ImageMimeType,
-						"data":     b64Data,
+					"fileData": map[string]interface{}{
+						"mimeType": part.MimeType,
+						"fileUri":  part.FileURI,
 					},
 				})
+				continue
+			}
+
+			data, mimeType := part.Data, part.MimeType
+			if len(data) == 0 && len(part.ImageData) > 0 {
+				data, mimeType = part.ImageData, part.ImageMimeType
 			}
+			if len(data) == 0 || mimeType == "" {
+				continue
+			}
+			if len(data) > inlineFileSizeThreshold {
+				return nil, NewValidationError(
+					fmt.Sprintf("blob of %d bytes exceeds the %d byte inline limit; upload it with Backend.UploadFile and reference it via ContentPart.FileURI instead", len(data), inlineFileSizeThreshold),
+					"Parts.Data", nil,
+				)
+			}
+			contentParts = append(contentParts, map[string]interface{}{
+				"inlineData": map[string]interface{}{
+					"mimeType": mimeType,
+					"data":     base64.StdEncoding.EncodeToString(data),
+				},
+			})
 		}
 
 		for _, tc := range msg.ToolCalls {
@@ -136,7 +259,7 @@ func (b *Backend) prepareMessages(messages []Message) []map[string]interface{} {
 		}
 	}
 
-	return result
+	return result, nil
 }
 
 func (b *Backend) prepareTools(tools []Tool) []map[string]interface{} {
@@ -330,7 +453,7 @@ func (b *Backend) buildRequestPayload(
 	thinkingConfig *ThinkingConfig,
 	tools []Tool,
 	projectID string,
-) map[string]interface{} {
+) (map[string]interface{}, error) {
 	genConfig := map[string]interface{}{
 		"temperature": 0.7,
 	}
@@ -350,6 +473,12 @@ func (b *Backend) buildRequestPayload(
 		if len(generationConfig.StopSequences) > 0 {
 			genConfig["stopSequences"] = generationConfig.StopSequences
 		}
+		if generationConfig.ResponseMimeType != "" {
+			genConfig["responseMimeType"] = generationConfig.ResponseMimeType
+		}
+		if generationConfig.ResponseSchema != nil {
+			genConfig["responseSchema"] = generationConfig.ResponseSchema
+		}
 	}
 
 	if thinkingConfig != nil && thinkingConfig.IncludeThoughts {
@@ -362,8 +491,13 @@ func (b *Backend) buildRequestPayload(
 		genConfig["thinkingConfig"] = thinkingCfg
 	}
 
+	contents, err := b.prepareMessages(messages)
+	if err != nil {
+		return nil, err
+	}
+
 	requestBody := map[string]interface{}{
-		"contents":         b.prepareMessages(messages),
+		"contents":         contents,
 		"generationConfig": genConfig,
 	}
 
@@ -380,10 +514,10 @@ func (b *Backend) buildRequestPayload(
 		payload["project"] = projectID
 	}
 
-	return payload
+	return payload, nil
 }
 
-func (b *Backend) parseCompletionResponse(data map[string]interface{}) *LLMChunk {
+func (b *Backend) parseCompletionResponse(data map[string]interface{}, generationConfig *GenerationConfig) *LLMChunk {
 	responseData := data
 	if resp, ok := data["response"].(map[string]interface{}); ok {
 		responseData = resp
@@ -448,13 +582,23 @@ func (b *Backend) parseCompletionResponse(data map[string]interface{}) *LLMChunk
 
 	finishReason, _ := candidate["finishReason"].(string)
 
-	return &LLMChunk{
+	chunk := &LLMChunk{
 		Content:          textContent,
 		ReasoningContent: reasoningContent,
 		ToolCalls:        toolCalls,
 		Usage:            usage,
 		FinishReason:     finishReason,
 	}
+
+	if generationConfig != nil {
+		chunk.responseSchema = generationConfig.ResponseSchema
+	}
+
+	if generationConfig != nil && generationConfig.ResponseMimeType == "application/json" && json.Valid([]byte(textContent)) {
+		chunk.StructuredContent = json.RawMessage(textContent)
+	}
+
+	return chunk
 }
 
 func getFloat(m map[string]interface{}, key string) float64 {
@@ -467,7 +611,171 @@ func getFloat(m map[string]interface{}, key string) float64 {
 	return 0
 }
 
-// Complete performs a non-streaming completion
+// isRetryableStatusCode reports whether statusCode is a transient failure
+// worth retrying with backoff (as opposed to 401/403, which are handled
+// separately via a forced credential refresh).
+func isRetryableStatusCode(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableTransportError reports whether err is a network-level failure
+// (connection reset, timeout, DNS failure, an AttemptTimeout expiring, ...)
+// rather than an error returned alongside a well-formed HTTP response.
+func isRetryableTransportError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses an HTTP Retry-After header, which is either a
+// number of seconds or an HTTP-date, returning 0 if absent or unparsable.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
+// doWithAttemptTimeout runs req through b.httpClient, bounding the time to
+// receive a response by b.retry.AttemptTimeout. Once a response has been
+// received within that window, its body is not subject to the timeout,
+// so a long streaming read is unaffected by a short AttemptTimeout.
+func (b *Backend) doWithAttemptTimeout(req *http.Request) (*http.Response, error) {
+	timeout := b.retry.AttemptTimeout
+	if timeout <= 0 {
+		return b.httpClient.Do(req)
+	}
+
+	attemptCtx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(attemptCtx)
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := b.httpClient.Do(req)
+		done <- result{resp, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			cancel()
+			return nil, r.err
+		}
+		// Defer cancellation to the body close instead of calling it here:
+		// cancelling now would tear down a successful streaming response
+		// before its caller gets to read it.
+		r.resp.Body = cancelOnCloseBody{ReadCloser: r.resp.Body, cancel: cancel}
+		return r.resp, nil
+	case <-timer.C:
+		cancel()
+		if r := <-done; r.resp != nil {
+			r.resp.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: attempt timed out after %s", context.DeadlineExceeded, timeout)
+	}
+}
+
+// cancelOnCloseBody calls cancel once the wrapped response body is closed,
+// releasing the per-attempt context doWithAttemptTimeout created for it.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// attemptOutcome is the result of a single HTTP attempt, classified so the
+// caller's retry loop knows whether to force a credential refresh, back off
+// and retry, or fail fast.
+type attemptOutcome struct {
+	err        error
+	authRetry  bool
+	transient  bool
+	retryAfter time.Duration
+}
+
+// retryLoop drives a single attempt function through b's RetryPolicy: it
+// forces a credential refresh and retries immediately on authRetry, backs
+// off with full jitter (honoring Retry-After when present) on a transient
+// outcome, returns non-retryable errors immediately, and wraps the final
+// error in a RetryExhaustedError once MaxAttempts is reached.
+func (b *Backend) retryLoop(ctx context.Context, attempt func(forceRefresh bool) attemptOutcome) error {
+	policy := b.retry
+	backoff := newFullJitterBackoff(policy.BaseDelay, policy.CapDelay)
+	forceRefresh := false
+	var lastErr error
+
+	for n := 1; n <= policy.MaxAttempts; n++ {
+		outcome := attempt(forceRefresh)
+		if outcome.err == nil {
+			return nil
+		}
+
+		lastErr = outcome.err
+		if !outcome.authRetry && !outcome.transient {
+			return outcome.err
+		}
+		if n == policy.MaxAttempts {
+			break
+		}
+
+		if outcome.authRetry {
+			b.oauthManager.InvalidateCredentials()
+			forceRefresh = true
+			if policy.OnRetry != nil {
+				policy.OnRetry(n, outcome.err, 0)
+			}
+			continue
+		}
+
+		forceRefresh = false
+		delay := outcome.retryAfter
+		if delay <= 0 {
+			delay = backoff.next()
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(n, outcome.err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return NewRetryExhaustedError(policy.MaxAttempts, lastErr)
+}
+
+// Complete performs a non-streaming completion, retrying transient failures
+// (429, 5xx, network errors) with capped exponential backoff and full
+// jitter per b.retry, and forcing a credential refresh on 401/403.
 func (b *Backend) Complete(
 	ctx context.Context,
 	model string,
@@ -476,65 +784,627 @@ func (b *Backend) Complete(
 	thinkingConfig *ThinkingConfig,
 	tools []Tool,
 ) (*LLMChunk, error) {
-	return b.completeWithRetry(ctx, model, messages, generationConfig, thinkingConfig, tools, 0)
+	var chunk *LLMChunk
+
+	err := b.retryLoop(ctx, func(forceRefresh bool) attemptOutcome {
+		c, outcome := b.completeAttempt(ctx, model, messages, generationConfig, thinkingConfig, tools, forceRefresh)
+		if outcome.err == nil {
+			chunk = c
+		}
+		return outcome
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chunk, nil
 }
 
-func (b *Backend) completeWithRetry(
+func (b *Backend) completeAttempt(
 	ctx context.Context,
 	model string,
 	messages []Message,
 	generationConfig *GenerationConfig,
 	thinkingConfig *ThinkingConfig,
 	tools []Tool,
-	retryCount int,
-) (*LLMChunk, error) {
-	headers, err := b.getAuthHeaders(retryCount > 0)
+	forceRefresh bool,
+) (*LLMChunk, attemptOutcome) {
+	headers, err := b.getAuthHeaders(ctx, forceRefresh)
 	if err != nil {
-		return nil, err
+		return nil, attemptOutcome{err: err}
 	}
 
 	accessToken := strings.TrimPrefix(headers["Authorization"], "Bearer ")
 	projectID, err := b.ensureProjectID(ctx, accessToken)
 	if err != nil {
-		return nil, err
+		return nil, attemptOutcome{err: err}
 	}
 
 	url := fmt.Sprintf("%s:generateContent", b.oauthManager.GetAPIEndpoint())
-	payload := b.buildRequestPayload(model, messages, generationConfig, thinkingConfig, tools, projectID)
+	payload, err := b.buildRequestPayload(model, messages, generationConfig, thinkingConfig, tools, projectID)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
 
 	reqBody, _ := json.Marshal(payload)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
-		return nil, err
+		return nil, attemptOutcome{err: err}
 	}
 
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.doWithAttemptTimeout(req)
+	if err != nil {
+		return nil, attemptOutcome{err: err, transient: isRetryableTransportError(err)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body)), authRetry: true}
+	}
+
+	if isRetryableStatusCode(resp.StatusCode) {
+		return nil, attemptOutcome{
+			err:        b.handleHTTPError(resp.StatusCode, string(body)),
+			transient:  true,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body))}
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	return b.parseCompletionResponse(data, generationConfig), attemptOutcome{}
+}
+
+// Embed generates embeddings for inputs against Gemini's embedContent (a
+// single input) or batchEmbedContents (more than one) endpoints, applying
+// the same retry policy as Complete.
+func (b *Backend) Embed(ctx context.Context, model string, inputs []EmbedInput, opts *EmbedOptions) (*EmbedResponse, error) {
+	if len(inputs) == 0 {
+		return &EmbedResponse{}, nil
+	}
+
+	var response *EmbedResponse
+
+	err := b.retryLoop(ctx, func(forceRefresh bool) attemptOutcome {
+		r, outcome := b.embedAttempt(ctx, model, inputs, opts, forceRefresh)
+		if outcome.err == nil {
+			response = r
+		}
+		return outcome
+	})
 	if err != nil {
 		return nil, err
 	}
+
+	return response, nil
+}
+
+func (b *Backend) embedAttempt(ctx context.Context, model string, inputs []EmbedInput, opts *EmbedOptions, forceRefresh bool) (*EmbedResponse, attemptOutcome) {
+	headers, err := b.getAuthHeaders(ctx, forceRefresh)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	accessToken := strings.TrimPrefix(headers["Authorization"], "Bearer ")
+	projectID, err := b.ensureProjectID(ctx, accessToken)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	endpointMethod := "embedContent"
+	if len(inputs) > 1 {
+		endpointMethod = "batchEmbedContents"
+	}
+	url := fmt.Sprintf("%s:%s", b.oauthManager.GetAPIEndpoint(), endpointMethod)
+	payload := b.buildEmbedPayload(model, inputs, opts, projectID)
+
+	reqBody, _ := json.Marshal(payload)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := b.doWithAttemptTimeout(req)
+	if err != nil {
+		return nil, attemptOutcome{err: err, transient: isRetryableTransportError(err)}
+	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
 
-	if (resp.StatusCode == 401 || resp.StatusCode == 403) && retryCount == 0 {
-		b.oauthManager.InvalidateCredentials()
-		return b.completeWithRetry(ctx, model, messages, generationConfig, thinkingConfig, tools, 1)
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body)), authRetry: true}
+	}
+
+	if isRetryableStatusCode(resp.StatusCode) {
+		return nil, attemptOutcome{
+			err:        b.handleHTTPError(resp.StatusCode, string(body)),
+			transient:  true,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, b.handleHTTPError(resp.StatusCode, string(body))
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body))}
 	}
 
 	var data map[string]interface{}
 	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	return parseEmbedResponse(data), attemptOutcome{}
+}
+
+func (b *Backend) buildEmbedPayload(model string, inputs []EmbedInput, opts *EmbedOptions, projectID string) map[string]interface{} {
+	var requestBody map[string]interface{}
+	if len(inputs) == 1 {
+		requestBody = embedContentRequest(model, inputs[0], opts)
+	} else {
+		requests := make([]map[string]interface{}, 0, len(inputs))
+		for _, in := range inputs {
+			requests = append(requests, embedContentRequest(model, in, opts))
+		}
+		requestBody = map[string]interface{}{"requests": requests}
+	}
+
+	payload := map[string]interface{}{
+		"model":   model,
+		"request": requestBody,
+	}
+
+	if projectID != "" {
+		payload["project"] = projectID
+	}
+
+	return payload
+}
+
+func embedContentRequest(model string, in EmbedInput, opts *EmbedOptions) map[string]interface{} {
+	body := map[string]interface{}{
+		"model": "models/" + model,
+		"content": map[string]interface{}{
+			"parts": []map[string]interface{}{{"text": in.Text}},
+		},
+	}
+
+	if in.TaskType != "" {
+		body["taskType"] = string(in.TaskType)
+	}
+	if in.Title != "" {
+		body["title"] = in.Title
+	}
+	if opts != nil {
+		if opts.OutputDimensionality > 0 {
+			body["outputDimensionality"] = opts.OutputDimensionality
+		}
+		if opts.AutoTruncate {
+			body["autoTruncate"] = true
+		}
+	}
+
+	return body
+}
+
+func parseEmbedResponse(data map[string]interface{}) *EmbedResponse {
+	responseData := data
+	if resp, ok := data["response"].(map[string]interface{}); ok {
+		responseData = resp
+	}
+
+	var vectors [][]float32
+
+	if emb, ok := responseData["embedding"].(map[string]interface{}); ok {
+		vectors = append(vectors, embeddingValues(emb))
+	}
+	if embs, ok := responseData["embeddings"].([]interface{}); ok {
+		for _, e := range embs {
+			if m, ok := e.(map[string]interface{}); ok {
+				vectors = append(vectors, embeddingValues(m))
+			}
+		}
+	}
+
+	var usage *LLMUsage
+	if u, ok := responseData["usageMetadata"].(map[string]interface{}); ok {
+		usage = &LLMUsage{
+			PromptTokens: int64(getFloat(u, "promptTokenCount")),
+			TotalTokens:  int64(getFloat(u, "totalTokenCount")),
+		}
+	}
+
+	return &EmbedResponse{Embeddings: vectors, Usage: usage}
+}
+
+func embeddingValues(embedding map[string]interface{}) []float32 {
+	raw, _ := embedding["values"].([]interface{})
+	values := make([]float32, 0, len(raw))
+	for _, v := range raw {
+		if f, ok := v.(float64); ok {
+			values = append(values, float32(f))
+		}
+	}
+	return values
+}
+
+// UploadFile performs a resumable upload to the Gemini Files API, following
+// the same init/chunk/commit pattern as google.golang.org/api's gensupport
+// resumable uploader: an init request opens an upload session, then data is
+// PUT in resumableUploadChunkSize chunks carrying a Content-Range header. A
+// transient failure mid-upload queries the session for how many bytes it
+// actually received and resumes from there instead of restarting, honoring
+// Retry-After and 308 partial-content responses along the way. The
+// returned FileRef can be attached to a message via ContentPart's
+// FileURI/MimeType fields instead of inlining the bytes.
+func (b *Backend) UploadFile(ctx context.Context, r io.Reader, mimeType, displayName string) (*FileRef, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading upload source: %w", err)
+	}
+
+	var sessionURL string
+	err = b.retryLoop(ctx, func(forceRefresh bool) attemptOutcome {
+		headers, err := b.getAuthHeaders(ctx, forceRefresh)
+		if err != nil {
+			return attemptOutcome{err: err}
+		}
+		url, outcome := b.initResumableUpload(ctx, headers, int64(len(data)), mimeType, displayName)
+		if outcome.err == nil {
+			sessionURL = url
+		}
+		return outcome
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return b.parseCompletionResponse(data), nil
+	return b.uploadChunksResumable(ctx, sessionURL, data)
+}
+
+// initResumableUpload opens an upload session for a blob of size bytes,
+// returning the session URL the caller PUTs chunks to.
+func (b *Backend) initResumableUpload(ctx context.Context, headers map[string]string, size int64, mimeType, displayName string) (string, attemptOutcome) {
+	url := fmt.Sprintf("%s/upload/%s/files?uploadType=resumable", GeminiCodeAssistEndpoint, GeminiCodeAssistAPIVersion)
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"file": map[string]interface{}{
+			"displayName": displayName,
+			"mimeType":    mimeType,
+		},
+	})
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(metadata))
+	if err != nil {
+		return "", attemptOutcome{err: err}
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("X-Goog-Upload-Protocol", "resumable")
+	req.Header.Set("X-Goog-Upload-Command", "start")
+	req.Header.Set("X-Goog-Upload-Header-Content-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("X-Goog-Upload-Header-Content-Type", mimeType)
+
+	resp, err := b.doWithAttemptTimeout(req)
+	if err != nil {
+		return "", attemptOutcome{err: err, transient: isRetryableTransportError(err)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return "", attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body)), authRetry: true}
+	}
+	if isRetryableStatusCode(resp.StatusCode) {
+		return "", attemptOutcome{
+			err:        b.handleHTTPError(resp.StatusCode, string(body)),
+			transient:  true,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body))}
+	}
+
+	sessionURL := resp.Header.Get("Location")
+	if sessionURL == "" {
+		return "", attemptOutcome{err: fmt.Errorf("upload init response did not include a session Location header")}
+	}
+	return sessionURL, attemptOutcome{}
+}
+
+// uploadChunksResumable PUTs data to sessionURL in resumableUploadChunkSize
+// chunks. A transient failure backs off (capped exponential, full jitter,
+// honoring Retry-After) and asks the session how much it actually received
+// before resuming, the same spirit as CompleteStreaming reconnecting a
+// dropped SSE stream without replaying what's already landed.
+func (b *Backend) uploadChunksResumable(ctx context.Context, sessionURL string, data []byte) (*FileRef, error) {
+	headers, err := b.getAuthHeaders(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := b.retry
+	backoff := newFullJitterBackoff(policy.BaseDelay, policy.CapDelay)
+	total := int64(len(data))
+	var offset int64
+	failedAttempts := 0
+
+	for offset < total {
+		end := offset + resumableUploadChunkSize
+		if end > total {
+			end = total
+		}
+
+		fileRef, next, outcome := b.putUploadChunk(ctx, headers, sessionURL, data[offset:end], offset, end, total)
+		if outcome.err == nil {
+			if fileRef != nil {
+				return fileRef, nil
+			}
+			offset = next
+			failedAttempts = 0
+			continue
+		}
+
+		if outcome.authRetry {
+			headers, err = b.getAuthHeaders(ctx, true)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		failedAttempts++
+		if !outcome.transient || failedAttempts >= policy.MaxAttempts {
+			return nil, outcome.err
+		}
+
+		if resumed, qerr := b.queryUploadOffset(ctx, headers, sessionURL, total); qerr == nil {
+			offset = resumed
+		}
+
+		delay := outcome.retryAfter
+		if delay <= 0 {
+			delay = backoff.next()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return nil, fmt.Errorf("upload session %s closed before the server returned a file resource", sessionURL)
+}
+
+// putUploadChunk PUTs data[start:end] of a total-byte upload to sessionURL.
+// It returns a non-nil FileRef once the server acknowledges the final
+// chunk with the committed file resource, or the offset to resume from
+// after a 308 partial-content response (which may be short of end if the
+// server only durably received part of the chunk).
+func (b *Backend) putUploadChunk(ctx context.Context, headers map[string]string, sessionURL string, chunk []byte, start, end, total int64) (*FileRef, int64, attemptOutcome) {
+	req, err := http.NewRequestWithContext(ctx, "PUT", sessionURL, bytes.NewReader(chunk))
+	if err != nil {
+		return nil, 0, attemptOutcome{err: err}
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, total))
+	req.Header.Set("Content-Length", strconv.FormatInt(end-start, 10))
+
+	resp, err := b.doWithAttemptTimeout(req)
+	if err != nil {
+		return nil, 0, attemptOutcome{err: err, transient: isRetryableTransportError(err)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, 0, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body)), authRetry: true}
+	}
+
+	if resp.StatusCode == http.StatusPermanentRedirect {
+		next := end
+		if received, ok := parseRangeEnd(resp.Header.Get("Range")); ok {
+			next = received + 1
+		}
+		if delay := parseRetryAfter(resp.Header.Get("Retry-After")); delay > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, 0, attemptOutcome{err: ctx.Err()}
+			case <-time.After(delay):
+			}
+		}
+		return nil, next, attemptOutcome{}
+	}
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		var data map[string]interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, 0, attemptOutcome{err: err}
+		}
+		fileData := data
+		if f, ok := data["file"].(map[string]interface{}); ok {
+			fileData = f
+		}
+		return fileRefFromMap(fileData), 0, attemptOutcome{}
+	}
+
+	if isRetryableStatusCode(resp.StatusCode) {
+		return nil, 0, attemptOutcome{
+			err:        b.handleHTTPError(resp.StatusCode, string(body)),
+			transient:  true,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	return nil, 0, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body))}
+}
+
+// queryUploadOffset asks sessionURL how many bytes of a total-byte upload
+// it has durably received, via a zero-length PUT with an unresolved
+// Content-Range, so a resumed upload doesn't re-send bytes the server
+// already has.
+func (b *Backend) queryUploadOffset(ctx context.Context, headers map[string]string, sessionURL string, total int64) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, "PUT", sessionURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", total))
+	req.Header.Set("Content-Length", "0")
+
+	resp, err := b.doWithAttemptTimeout(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusPermanentRedirect {
+		return 0, fmt.Errorf("unexpected status %d querying upload offset", resp.StatusCode)
+	}
+
+	if received, ok := parseRangeEnd(resp.Header.Get("Range")); ok {
+		return received + 1, nil
+	}
+	return 0, nil
+}
+
+// parseRangeEnd extracts the end offset from a "bytes=0-N" or "bytes 0-N/T"
+// Range header, as returned alongside a 308 partial-content response.
+func parseRangeEnd(header string) (int64, bool) {
+	header = strings.TrimPrefix(header, "bytes=")
+	header = strings.TrimPrefix(header, "bytes ")
+	bounds := strings.SplitN(strings.SplitN(header, "/", 2)[0], "-", 2)
+	if len(bounds) != 2 {
+		return 0, false
+	}
+	end, err := strconv.ParseInt(bounds[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return end, true
+}
+
+// fileRefFromMap decodes a Files API file resource (the shape returned by
+// both the upload commit response and Backend.GetFile) into a FileRef.
+func fileRefFromMap(m map[string]interface{}) *FileRef {
+	uri, _ := m["uri"].(string)
+	name, _ := m["name"].(string)
+	mimeType, _ := m["mimeType"].(string)
+	state, _ := m["state"].(string)
+
+	var sizeBytes int64
+	if s, ok := m["sizeBytes"].(string); ok {
+		sizeBytes, _ = strconv.ParseInt(s, 10, 64)
+	}
+
+	return &FileRef{
+		URI:       uri,
+		Name:      name,
+		MimeType:  mimeType,
+		SizeBytes: sizeBytes,
+		State:     state,
+	}
+}
+
+// GetFile retrieves the metadata of a file previously uploaded via
+// UploadFile, addressed by its FileRef.Name (e.g. "files/abc-123").
+func (b *Backend) GetFile(ctx context.Context, name string) (*FileRef, error) {
+	var fileRef *FileRef
+	err := b.retryLoop(ctx, func(forceRefresh bool) attemptOutcome {
+		ref, outcome := b.fileRequestAttempt(ctx, "GET", name, forceRefresh)
+		if outcome.err == nil {
+			fileRef = ref
+		}
+		return outcome
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fileRef, nil
+}
+
+// DeleteFile removes a file previously uploaded via UploadFile, addressed
+// by its FileRef.Name.
+func (b *Backend) DeleteFile(ctx context.Context, name string) error {
+	return b.retryLoop(ctx, func(forceRefresh bool) attemptOutcome {
+		_, outcome := b.fileRequestAttempt(ctx, "DELETE", name, forceRefresh)
+		return outcome
+	})
+}
+
+func (b *Backend) fileRequestAttempt(ctx context.Context, method, name string, forceRefresh bool) (*FileRef, attemptOutcome) {
+	headers, err := b.getAuthHeaders(ctx, forceRefresh)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", GeminiCodeAssistEndpoint, GeminiCodeAssistAPIVersion, name)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := b.doWithAttemptTimeout(req)
+	if err != nil {
+		return nil, attemptOutcome{err: err, transient: isRetryableTransportError(err)}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body)), authRetry: true}
+	}
+	if isRetryableStatusCode(resp.StatusCode) {
+		return nil, attemptOutcome{
+			err:        b.handleHTTPError(resp.StatusCode, string(body)),
+			transient:  true,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body))}
+	}
+
+	if method == "DELETE" {
+		return nil, attemptOutcome{}
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+	return fileRefFromMap(data), attemptOutcome{}
 }
 
 // ChunkChannel is used for streaming responses
@@ -546,7 +1416,14 @@ type StreamResult struct {
 	Error error
 }
 
-// CompleteStreaming performs a streaming completion
+// CompleteStreaming performs a streaming completion with the default
+// StreamReaderOptions (a 100-chunk blocking buffer), returning just the
+// channel for callers that don't need StreamReader's deadline, Close, or
+// backpressure controls. The initial connection is retried synchronously
+// per b.retry, same as Complete; once the stream is open, the returned
+// channel's goroutine transparently reconnects on a transient failure as
+// long as no chunk has been dispatched yet, and fails fast (no replay)
+// once the caller has seen any data.
 func (b *Backend) CompleteStreaming(
 	ctx context.Context,
 	model string,
@@ -555,94 +1432,238 @@ func (b *Backend) CompleteStreaming(
 	thinkingConfig *ThinkingConfig,
 	tools []Tool,
 ) (ChunkChannel, error) {
-	return b.completeStreamingWithRetry(ctx, model, messages, generationConfig, thinkingConfig, tools, 0)
+	reader, err := b.CompleteStreamingReader(ctx, model, messages, generationConfig, thinkingConfig, tools, nil)
+	if err != nil {
+		return nil, err
+	}
+	return reader.Chunks(), nil
 }
 
-func (b *Backend) completeStreamingWithRetry(
+// CompleteStreamingReader performs a streaming completion like
+// CompleteStreaming, but returns a StreamReader: cancelling ctx or calling
+// StreamReader.Close tears down the underlying connection and unblocks a
+// read parked mid-chunk, SetReadDeadline arms an additional deadline
+// independent of ctx, and opts.Backpressure controls what happens once
+// opts.BufferSize chunks are buffered and unread.
+func (b *Backend) CompleteStreamingReader(
 	ctx context.Context,
 	model string,
 	messages []Message,
 	generationConfig *GenerationConfig,
 	thinkingConfig *ThinkingConfig,
 	tools []Tool,
-	retryCount int,
-) (ChunkChannel, error) {
-	headers, err := b.getAuthHeaders(retryCount > 0)
+	opts *StreamReaderOptions,
+) (*StreamReader, error) {
+	opts = opts.withDefaults()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	resp, err := b.connectStream(streamCtx, model, messages, generationConfig, thinkingConfig, tools)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
+	dl := newDeadline()
+	ch := make(chan StreamResult, opts.BufferSize)
+	go b.runStream(streamCtx, resp, model, messages, generationConfig, thinkingConfig, tools, dl, opts, ch)
+
+	return &StreamReader{ch: ch, dl: dl, cancel: cancel}, nil
+}
+
+// connectStream establishes a streaming connection, applying the same
+// retry/backoff policy as Complete.
+func (b *Backend) connectStream(
+	ctx context.Context,
+	model string,
+	messages []Message,
+	generationConfig *GenerationConfig,
+	thinkingConfig *ThinkingConfig,
+	tools []Tool,
+) (*http.Response, error) {
+	var resp *http.Response
+
+	err := b.retryLoop(ctx, func(forceRefresh bool) attemptOutcome {
+		r, outcome := b.connectStreamAttempt(ctx, model, messages, generationConfig, thinkingConfig, tools, forceRefresh)
+		if outcome.err == nil {
+			resp = r
+		}
+		return outcome
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (b *Backend) connectStreamAttempt(
+	ctx context.Context,
+	model string,
+	messages []Message,
+	generationConfig *GenerationConfig,
+	thinkingConfig *ThinkingConfig,
+	tools []Tool,
+	forceRefresh bool,
+) (*http.Response, attemptOutcome) {
+	headers, err := b.getAuthHeaders(ctx, forceRefresh)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
 	accessToken := strings.TrimPrefix(headers["Authorization"], "Bearer ")
 	projectID, err := b.ensureProjectID(ctx, accessToken)
 	if err != nil {
-		return nil, err
+		return nil, attemptOutcome{err: err}
 	}
 
 	url := fmt.Sprintf("%s:streamGenerateContent?alt=sse", b.oauthManager.GetAPIEndpoint())
-	payload := b.buildRequestPayload(model, messages, generationConfig, thinkingConfig, tools, projectID)
+	payload, err := b.buildRequestPayload(model, messages, generationConfig, thinkingConfig, tools, projectID)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
 
 	reqBody, _ := json.Marshal(payload)
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
-		return nil, err
+		return nil, attemptOutcome{err: err}
 	}
 
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
 
-	resp, err := b.httpClient.Do(req)
+	resp, err := b.doWithAttemptTimeout(req)
 	if err != nil {
-		return nil, err
+		return nil, attemptOutcome{err: err, transient: isRetryableTransportError(err)}
 	}
 
-	if (resp.StatusCode == 401 || resp.StatusCode == 403) && retryCount == 0 {
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		b.oauthManager.InvalidateCredentials()
-		return b.completeStreamingWithRetry(ctx, model, messages, generationConfig, thinkingConfig, tools, 1)
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body)), authRetry: true}
+	}
+
+	if isRetryableStatusCode(resp.StatusCode) {
+		body, _ := io.ReadAll(resp.Body)
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+		resp.Body.Close()
+		return nil, attemptOutcome{
+			err:        b.handleHTTPError(resp.StatusCode, string(body)),
+			transient:  true,
+			retryAfter: retryAfter,
+		}
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, b.handleHTTPError(resp.StatusCode, string(body))
+		return nil, attemptOutcome{err: b.handleHTTPError(resp.StatusCode, string(body))}
 	}
 
-	ch := make(chan StreamResult, 100)
+	return resp, attemptOutcome{}
+}
 
-	go func() {
-		defer close(ch)
-		defer resp.Body.Close()
+// runStream reads SSE chunks from resp and writes them to ch, reconnecting
+// through connectStream on a transient failure as long as dispatched is
+// still false, and failing fast otherwise. A ctx cancellation (including
+// StreamReader.Close) is treated as terminal, never as a reason to
+// reconnect. It owns ch and closes it when the stream ends or a
+// non-recoverable error occurs.
+func (b *Backend) runStream(
+	ctx context.Context,
+	resp *http.Response,
+	model string,
+	messages []Message,
+	generationConfig *GenerationConfig,
+	thinkingConfig *ThinkingConfig,
+	tools []Tool,
+	dl *deadline,
+	opts *StreamReaderOptions,
+	ch chan StreamResult,
+) {
+	defer close(ch)
 
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if line == "" || strings.HasPrefix(line, ":") {
-				continue
-			}
+	dispatchedAny := false
 
-			if strings.HasPrefix(line, "data:") {
-				data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
-				if data == "[DONE]" {
-					continue
-				}
+	for {
+		readErr, dispatched := b.drainStream(ctx, resp, dl, generationConfig, opts, ch)
+		resp.Body.Close()
+		if dispatched {
+			dispatchedAny = true
+		}
 
-				var parsed map[string]interface{}
-				if err := json.Unmarshal([]byte(data), &parsed); err != nil {
-					continue
-				}
+		if readErr == nil {
+			return
+		}
 
-				chunk := b.parseCompletionResponse(parsed)
-				ch <- StreamResult{Chunk: chunk}
+		if ctx.Err() != nil {
+			if dispatchedAny {
+				ch <- StreamResult{Error: NewCancellationError(ctx.Err().Error())}
 			}
+			return
+		}
+
+		if dispatchedAny {
+			ch <- StreamResult{Error: NewStreamError(readErr.Error())}
+			return
+		}
+
+		next, err := b.connectStream(ctx, model, messages, generationConfig, thinkingConfig, tools)
+		if err != nil {
+			ch <- StreamResult{Error: err}
+			return
 		}
+		resp = next
+	}
+}
 
-		if err := scanner.Err(); err != nil {
-			ch <- StreamResult{Error: NewStreamError(err.Error())}
+// drainStream reads `data:` SSE lines from resp.Body until EOF or error,
+// dispatching each parsed chunk to ch per opts.Backpressure. It reports
+// the read error (if any) and whether at least one chunk was dispatched.
+//
+// Reads go through a deadlineReader racing ctx.Done() and dl, so
+// cancelling ctx or arming dl via StreamReader.SetReadDeadline unblocks a
+// read parked mid-line instead of hanging until the next byte arrives;
+// lines are read with ReadLineWithLimit instead of bufio.Scanner, whose
+// fixed 64KB line cap would silently truncate a long tool-call response.
+func (b *Backend) drainStream(ctx context.Context, resp *http.Response, dl *deadline, generationConfig *GenerationConfig, opts *StreamReaderOptions, ch chan StreamResult) (error, bool) {
+	dispatched := false
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Body.Close()
+		case <-stop:
 		}
 	}()
 
-	return ch, nil
+	reader := bufio.NewReader(&deadlineReader{r: resp.Body, ctx: ctx, dl: dl})
+	for {
+		line, err := ReadLineWithLimit(reader, opts.MaxLineSize)
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, ":") && strings.HasPrefix(trimmed, "data:") {
+			data := strings.TrimSpace(strings.TrimPrefix(trimmed, "data:"))
+			if data != "[DONE]" {
+				var parsed map[string]interface{}
+				if jsonErr := json.Unmarshal([]byte(data), &parsed); jsonErr == nil {
+					chunk := b.parseCompletionResponse(parsed, generationConfig)
+					dispatchChunk(ch, StreamResult{Chunk: chunk}, opts.Backpressure)
+					dispatched = true
+				}
+			}
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return nil, dispatched
+			}
+			return err, dispatched
+		}
+	}
 }
 
 func (b *Backend) handleHTTPError(statusCode int, body string) error {
@@ -687,6 +1708,16 @@ func (b *Backend) ListModels() []string {
 	return names
 }
 
+// ListEmbeddingModels returns available embedding models
+func (b *Backend) ListEmbeddingModels() []string {
+	models := GetGeminiCLIEmbeddingModels()
+	var names []string
+	for name := range models {
+		names = append(names, name)
+	}
+	return names
+}
+
 // GetOAuthManager returns the OAuth manager
 func (b *Backend) GetOAuthManager() *OAuthManager {
 	return b.oauthManager
@@ -2,19 +2,39 @@ package geminisdk
 
 import (
 	"context"
+	"errors"
+	"math/rand"
+	"strings"
 	"sync"
 	"time"
 )
 
+// minRefreshFloor bounds how aggressively the auto-refresh scheduler can
+// re-arm its timer, even if a token's expiry window is very short.
+const minRefreshFloor = 5 * time.Second
+
+// refreshJitterWindow is the maximum jitter subtracted from a computed
+// refresh delay, spreading out sessions that would otherwise all wake at
+// the same instant.
+const refreshJitterWindow = 30 * time.Second
+
 // Client is the main entry point for the GeminiSDK
 type Client struct {
-	options      *ClientOptions
-	state        ConnectionState
-	backend      *Backend
-	oauthManager *OAuthManager
-	sessions     map[string]*Session
-	started      bool
-	mu           sync.RWMutex
+	options          *ClientOptions
+	state            ConnectionState
+	backend          *Backend
+	provider         ChatCompletionProvider
+	customProvider   ChatCompletionProvider
+	oauthManager     *OAuthManager
+	credentialSource CredentialSource
+	profileStore     *ProfileCredentialStore
+	sessions         map[string]*Session
+	sessionStore     SessionStore
+	autoPersist      bool
+	authEvents       chan AuthEvent
+	started          bool
+	refreshCancel    context.CancelFunc
+	mu               sync.RWMutex
 }
 
 // NewClient creates a new GeminiSDK client
@@ -26,10 +46,37 @@ func NewClient(options *ClientOptions) *Client {
 	}
 
 	return &Client{
-		options:  options,
-		state:    StateDisconnected,
-		sessions: make(map[string]*Session),
-		started:  false,
+		options:    options,
+		state:      StateDisconnected,
+		sessions:   make(map[string]*Session),
+		authEvents: make(chan AuthEvent, 16),
+		started:    false,
+	}
+}
+
+// NewClientWithProvider creates a Client that sends completions through
+// provider instead of the default Gemini CLI OAuth backend — e.g. a
+// providers/google API-key-based ChatCompletionProvider. Start skips OAuth
+// setup entirely in this mode, since authentication is the provider's own
+// responsibility.
+func NewClientWithProvider(provider ChatCompletionProvider, options *ClientOptions) *Client {
+	c := NewClient(options)
+	c.provider = provider
+	c.customProvider = provider
+	return c
+}
+
+// AuthEvents returns a channel of RefreshedEvent, RefreshFailedEvent, and
+// AuthInvalidatedEvent values emitted by the auto-refresh scheduler, so
+// callers can learn programmatically when their credentials go stale.
+func (c *Client) AuthEvents() <-chan AuthEvent {
+	return c.authEvents
+}
+
+func (c *Client) emitAuthEvent(event AuthEvent) {
+	select {
+	case c.authEvents <- event:
+	default:
 	}
 }
 
@@ -49,12 +96,33 @@ func (c *Client) Start(ctx context.Context) error {
 	}
 
 	c.state = StateConnecting
+	customProvider := c.provider != nil
 	c.mu.Unlock()
 
+	if customProvider {
+		return c.startWithProvider(ctx)
+	}
+
+	credentialStore := c.options.CredentialStore
+	var profileStore *ProfileCredentialStore
+	if credentialStore == nil && len(c.options.Profiles) > 0 {
+		profileStore = NewProfileCredentialStore(c.options.OAuthPath, c.options.Profiles)
+		if c.options.Profile != "" {
+			_ = profileStore.Use(c.options.Profile)
+		}
+		credentialStore = profileStore
+	}
+
+	var oauthOpts []OAuthManagerOption
+	if credentialStore != nil {
+		oauthOpts = append(oauthOpts, WithCredentialStore(credentialStore))
+	}
+
 	oauthManager := NewOAuthManager(
 		c.options.OAuthPath,
 		c.options.ClientID,
 		c.options.ClientSecret,
+		oauthOpts...,
 	)
 
 	timeout := 720 * time.Second
@@ -63,14 +131,25 @@ func (c *Client) Start(ctx context.Context) error {
 	}
 
 	backend := NewBackend(&BackendOptions{
-		Timeout:      timeout,
-		OAuthPath:    c.options.OAuthPath,
-		ClientID:     c.options.ClientID,
-		ClientSecret: c.options.ClientSecret,
+		Timeout:          timeout,
+		OAuthPath:        c.options.OAuthPath,
+		ClientID:         c.options.ClientID,
+		ClientSecret:     c.options.ClientSecret,
+		CredentialStore:  credentialStore,
+		CredentialSource: c.options.CredentialSource,
 	})
 
-	// Verify authentication
-	if _, err := oauthManager.EnsureAuthenticated(false); err != nil {
+	// Verify authentication, using the configured CredentialSource (service
+	// account, ADC, workload identity) when present, falling back to the
+	// cached-OAuth flow otherwise.
+	if c.options.CredentialSource != nil {
+		if _, err := c.options.CredentialSource.Token(ctx); err != nil {
+			c.mu.Lock()
+			c.state = StateError
+			c.mu.Unlock()
+			return err
+		}
+	} else if _, err := oauthManager.EnsureAuthenticated(false); err != nil {
 		c.mu.Lock()
 		c.state = StateError
 		c.mu.Unlock()
@@ -79,37 +158,173 @@ func (c *Client) Start(ctx context.Context) error {
 
 	c.mu.Lock()
 	c.oauthManager = oauthManager
+	c.credentialSource = c.options.CredentialSource
+	c.profileStore = profileStore
 	c.backend = backend
+	c.provider = backend
+	c.sessionStore = c.options.SessionStore
+	c.autoPersist = c.options.AutoPersist
 	c.state = StateConnected
 	c.started = true
 	c.mu.Unlock()
 
 	// Start auto-refresh if enabled
 	if c.options.AutoRefresh {
-		go c.autoRefreshLoop(ctx)
+		refreshCtx, cancel := context.WithCancel(ctx)
+		c.mu.Lock()
+		c.refreshCancel = cancel
+		c.mu.Unlock()
+		go c.autoRefreshLoop(refreshCtx)
+	}
+
+	// Rehydrate any sessions persisted by a previous process
+	if c.sessionStore != nil {
+		c.rehydrateSessions(ctx)
 	}
 
 	return nil
 }
 
+// startWithProvider initializes the client when NewClientWithProvider
+// supplied a ChatCompletionProvider, skipping OAuth/backend setup entirely
+// since the provider owns its own authentication.
+func (c *Client) startWithProvider(ctx context.Context) error {
+	c.mu.Lock()
+	c.sessionStore = c.options.SessionStore
+	c.autoPersist = c.options.AutoPersist
+	c.state = StateConnected
+	c.started = true
+	c.mu.Unlock()
+
+	if c.sessionStore != nil {
+		c.rehydrateSessions(ctx)
+	}
+
+	return nil
+}
+
+// rehydrateSessions reloads every session known to the configured
+// SessionStore and re-registers it in c.sessions.
+func (c *Client) rehydrateSessions(ctx context.Context) {
+	c.mu.RLock()
+	store := c.sessionStore
+	provider := c.provider
+	c.mu.RUnlock()
+
+	if store == nil {
+		return
+	}
+
+	ids, err := store.List(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, id := range ids {
+		snapshot, err := store.Load(ctx, id)
+		if err != nil {
+			continue
+		}
+
+		session := RestoreSession(snapshot, provider)
+		c.mu.Lock()
+		c.sessions[id] = session
+		c.mu.Unlock()
+	}
+}
+
+// autoRefreshLoop replaces naive 30-second polling with an event-driven
+// scheduler: after each successful refresh it arms a single timer for
+// exactly when the token next needs renewing, and on failure it backs off
+// exponentially with full jitter instead of hammering the token endpoint.
 func (c *Client) autoRefreshLoop(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
+	backoff := newFullJitterBackoff(time.Second, 5*time.Minute)
+
+	timer := time.NewTimer(c.nextRefreshDelay())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			c.mu.RLock()
 			manager := c.oauthManager
 			c.mu.RUnlock()
 
-			if manager != nil {
-				manager.EnsureAuthenticated(false)
+			if manager == nil {
+				return
 			}
+
+			if _, err := manager.EnsureAuthenticated(true); err != nil {
+				if isTerminalAuthError(err) {
+					c.emitAuthEvent(AuthInvalidatedEvent{})
+					return
+				}
+
+				wait := backoff.next()
+				c.emitAuthEvent(RefreshFailedEvent{Err: err, NextAttempt: time.Now().Add(wait)})
+				timer.Reset(wait)
+				continue
+			}
+
+			backoff.reset()
+
+			var expiresAt time.Time
+			if creds := manager.peekCredentials(); creds != nil && creds.ExpiryDate > 0 {
+				expiresAt = time.UnixMilli(creds.ExpiryDate)
+			}
+			c.emitAuthEvent(RefreshedEvent{ExpiresAt: expiresAt})
+			timer.Reset(c.nextRefreshDelay())
+		}
+	}
+}
+
+// nextRefreshDelay computes how long to sleep before the next proactive
+// refresh: the time until the cached token's expiry, minus the refresh
+// buffer and a random jitter, floored at minRefreshFloor.
+func (c *Client) nextRefreshDelay() time.Duration {
+	c.mu.RLock()
+	manager := c.oauthManager
+	c.mu.RUnlock()
+
+	if manager == nil {
+		return minRefreshFloor
+	}
+
+	creds := manager.peekCredentials()
+	if creds == nil || creds.ExpiryDate == 0 {
+		return minRefreshFloor
+	}
+
+	expiresIn := time.Until(time.UnixMilli(creds.ExpiryDate))
+	jitter := time.Duration(rand.Int63n(int64(refreshJitterWindow)))
+	sleep := expiresIn - TokenRefreshBufferMs*time.Millisecond - jitter
+
+	if sleep < minRefreshFloor {
+		sleep = minRefreshFloor
+	}
+	return sleep
+}
+
+// isTerminalAuthError reports whether err indicates credentials that no
+// further retry can fix (missing or revoked refresh token), as opposed to
+// a transient failure worth backing off and retrying.
+func isTerminalAuthError(err error) bool {
+	if errors.Is(err, ErrCredentialsNotFound) {
+		return true
+	}
+
+	var refreshErr *TokenRefreshError
+	if errors.As(err, &refreshErr) {
+		if refreshErr.Message == "No refresh token available" ||
+			strings.Contains(refreshErr.Message, "invalid_grant") ||
+			strings.Contains(refreshErr.ResponseBody, "invalid_grant") {
+			return true
 		}
 	}
+
+	return false
 }
 
 // Stop shuts down the client
@@ -123,7 +338,13 @@ func (c *Client) Stop() error {
 	}
 	c.sessions = make(map[string]*Session)
 
+	if c.refreshCancel != nil {
+		c.refreshCancel()
+		c.refreshCancel = nil
+	}
+
 	c.backend = nil
+	c.provider = c.customProvider
 	c.oauthManager = nil
 	c.state = StateDisconnected
 	c.started = false
@@ -140,7 +361,7 @@ func (c *Client) Close() error {
 func (c *Client) CreateSession(ctx context.Context, config *SessionConfig) (*Session, error) {
 	c.mu.RLock()
 	started := c.started
-	backend := c.backend
+	provider := c.provider
 	c.mu.RUnlock()
 
 	if !started {
@@ -148,11 +369,11 @@ func (c *Client) CreateSession(ctx context.Context, config *SessionConfig) (*Ses
 			return nil, err
 		}
 		c.mu.RLock()
-		backend = c.backend
+		provider = c.provider
 		c.mu.RUnlock()
 	}
 
-	if backend == nil {
+	if provider == nil {
 		return nil, NewConfigurationError("Client not connected")
 	}
 
@@ -169,7 +390,7 @@ func (c *Client) CreateSession(ctx context.Context, config *SessionConfig) (*Ses
 	session := NewSession(
 		sessionID,
 		model,
-		backend,
+		provider,
 		config.Tools,
 		config.SystemMessage,
 		config.GenerationConfig,
@@ -177,13 +398,187 @@ func (c *Client) CreateSession(ctx context.Context, config *SessionConfig) (*Ses
 		config.Streaming,
 	)
 
+	if config.ApprovalPolicy != nil {
+		session.SetApprovalPolicy(config.ApprovalPolicy)
+	}
+	if config.ManualToolDispatch {
+		session.SetManualToolDispatch(true)
+	}
+
 	c.mu.Lock()
 	c.sessions[sessionID] = session
+	autoPersist := c.autoPersist
+	store := c.sessionStore
+	profileStore := c.profileStore
+	c.mu.Unlock()
+
+	if autoPersist && store != nil {
+		session.SetSessionStore(store)
+		session.On(func(event SessionEvent) {
+			if event.EventType == EventSessionIdle || isSessionErrorEvent(event.EventType) {
+				_ = store.Save(context.Background(), session.Snapshot())
+			}
+		})
+	}
+
+	c.wireProfileRotation(session, profileStore)
+
+	return session, nil
+}
+
+// wireProfileRotation registers a listener that rotates to the next
+// configured profile (and invalidates cached credentials) whenever session
+// reports EventSessionQuotaExceeded. profileStore may be nil, in which case
+// this is a no-op.
+func (c *Client) wireProfileRotation(session *Session, profileStore *ProfileCredentialStore) {
+	if profileStore == nil {
+		return
+	}
+
+	session.On(func(event SessionEvent) {
+		if event.EventType != EventSessionQuotaExceeded {
+			return
+		}
+		if profileStore.RotateOnQuotaError(ErrQuotaExceeded) {
+			c.mu.RLock()
+			manager := c.oauthManager
+			backend := c.backend
+			c.mu.RUnlock()
+			if manager != nil {
+				manager.InvalidateCredentials()
+			}
+			// The backend authenticates requests through its own manager.
+			if backend != nil {
+				backend.GetOAuthManager().InvalidateCredentials()
+			}
+		}
+	})
+}
+
+// CreateSessionForAgent creates a session bound to agent: its system
+// prompt, registered tools, and default generation/thinking config are
+// wired in automatically. overrides may be nil; any non-zero field on it
+// takes precedence over the agent's defaults.
+func (c *Client) CreateSessionForAgent(ctx context.Context, agent *Agent, overrides *SessionConfig) (*Session, error) {
+	if agent == nil {
+		return nil, NewConfigurationError("agent is required")
+	}
+
+	config := &SessionConfig{
+		SystemMessage:    agent.SystemPrompt,
+		GenerationConfig: agent.GenerationConfig,
+		ThinkingConfig:   agent.ThinkingConfig,
+	}
+
+	if agent.Tools != nil {
+		config.Tools = agent.Tools.Tools()
+	}
+
+	if overrides != nil {
+		if overrides.SessionID != "" {
+			config.SessionID = overrides.SessionID
+		}
+		if overrides.Model != "" {
+			config.Model = overrides.Model
+		}
+		if overrides.SystemMessage != "" {
+			config.SystemMessage = overrides.SystemMessage
+		}
+		if overrides.GenerationConfig != nil {
+			config.GenerationConfig = overrides.GenerationConfig
+		}
+		if overrides.ThinkingConfig != nil {
+			config.ThinkingConfig = overrides.ThinkingConfig
+		}
+		if len(overrides.Tools) > 0 {
+			config.Tools = overrides.Tools
+		}
+		config.Streaming = overrides.Streaming
+		config.ApprovalPolicy = overrides.ApprovalPolicy
+		config.ManualToolDispatch = overrides.ManualToolDispatch
+	}
+
+	session, err := c.CreateSession(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if agent.Tools != nil {
+		for _, name := range agent.Tools.Names() {
+			if handler, ok := agent.Tools.GetHandler(name); ok {
+				session.RegisterToolHandler(name, handler)
+			}
+		}
+	}
+
+	return session, nil
+}
+
+// SaveSession persists the given session's current state via the
+// configured SessionStore.
+func (c *Client) SaveSession(id string) error {
+	c.mu.RLock()
+	store := c.sessionStore
+	session, ok := c.sessions[id]
+	c.mu.RUnlock()
+
+	if store == nil {
+		return NewConfigurationError("no SessionStore configured")
+	}
+	if !ok {
+		return NewSessionNotFoundError(id)
+	}
+
+	return store.Save(context.Background(), session.Snapshot())
+}
+
+// LoadSession rehydrates a session from the configured SessionStore and
+// re-registers it with the client, returning the live Session.
+func (c *Client) LoadSession(ctx context.Context, id string) (*Session, error) {
+	c.mu.RLock()
+	store := c.sessionStore
+	provider := c.provider
+	autoPersist := c.autoPersist
+	profileStore := c.profileStore
+	c.mu.RUnlock()
+
+	if store == nil {
+		return nil, NewConfigurationError("no SessionStore configured")
+	}
+
+	snapshot, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	session := RestoreSession(snapshot, provider)
+
+	if autoPersist {
+		session.SetSessionStore(store)
+		session.On(func(event SessionEvent) {
+			if event.EventType == EventSessionIdle || isSessionErrorEvent(event.EventType) {
+				_ = store.Save(context.Background(), session.Snapshot())
+			}
+		})
+	}
+
+	c.wireProfileRotation(session, profileStore)
+
+	c.mu.Lock()
+	c.sessions[id] = session
 	c.mu.Unlock()
 
 	return session, nil
 }
 
+// ResumeSession is an alias for LoadSession: it reconstructs a live Session
+// (model, system message, history, tools, and branching metadata) from the
+// configured SessionStore so a CLI can continue a conversation across
+// process restarts.
+func (c *Client) ResumeSession(ctx context.Context, sessionID string) (*Session, error) {
+	return c.LoadSession(ctx, sessionID)
+}
+
 // GetSession returns an existing session by ID
 func (c *Client) GetSession(sessionID string) (*Session, error) {
 	c.mu.RLock()
@@ -282,8 +677,14 @@ func (c *Client) ListModels() []ModelInfo {
 func (c *Client) RefreshAuth() error {
 	c.mu.RLock()
 	manager := c.oauthManager
+	credSource := c.credentialSource
 	c.mu.RUnlock()
 
+	if credSource != nil {
+		_, err := credSource.Token(context.Background())
+		return err
+	}
+
 	if manager != nil {
 		_, err := manager.EnsureAuthenticated(true)
 		return err
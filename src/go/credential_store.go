@@ -0,0 +1,213 @@
+package geminisdk
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+// CredentialStore persists and retrieves OAuth credentials for OAuthManager.
+// Implementations must be safe for concurrent use.
+type CredentialStore interface {
+	// Load returns the currently stored credentials, or a
+	// CredentialsNotFoundError if none have been saved yet.
+	Load(ctx context.Context) (*GeminiOAuthCredentials, error)
+	// Save persists credentials, overwriting any previous value.
+	Save(ctx context.Context, creds *GeminiOAuthCredentials) error
+	// Delete removes any stored credentials. It is a no-op if none exist.
+	Delete(ctx context.Context) error
+}
+
+// FileCredentialStore persists credentials as JSON on disk, matching the
+// layout the Gemini CLI itself uses under ~/.gemini.
+type FileCredentialStore struct {
+	path string
+}
+
+// NewFileCredentialStore creates a store rooted at path. An empty path
+// resolves to the default Gemini CLI credential path.
+func NewFileCredentialStore(path string) *FileCredentialStore {
+	return &FileCredentialStore{path: GetGeminiCLICredentialPath(path)}
+}
+
+func (s *FileCredentialStore) Load(ctx context.Context) (*GeminiOAuthCredentials, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewCredentialsNotFoundError(s.path)
+		}
+		return nil, err
+	}
+
+	var creds GeminiOAuthCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+
+	return &creds, nil
+}
+
+func (s *FileCredentialStore) Save(ctx context.Context, creds *GeminiOAuthCredentials) error {
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0600)
+}
+
+func (s *FileCredentialStore) Delete(ctx context.Context) error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// EnvCredentialStore reads and writes credentials as base64-encoded JSON in
+// an environment variable, which suits containers and CI pipelines where
+// writing a file under ~/.gemini isn't practical.
+type EnvCredentialStore struct {
+	varName string
+}
+
+// NewEnvCredentialStore creates a store backed by the given environment
+// variable. An empty varName defaults to GEMINI_OAUTH_CREDENTIALS.
+func NewEnvCredentialStore(varName string) *EnvCredentialStore {
+	if varName == "" {
+		varName = "GEMINI_OAUTH_CREDENTIALS"
+	}
+	return &EnvCredentialStore{varName: varName}
+}
+
+func (s *EnvCredentialStore) Load(ctx context.Context) (*GeminiOAuthCredentials, error) {
+	encoded := os.Getenv(s.varName)
+	if encoded == "" {
+		return nil, NewCredentialsNotFoundError(s.varName)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds GeminiOAuthCredentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+
+	return &creds, nil
+}
+
+func (s *EnvCredentialStore) Save(ctx context.Context, creds *GeminiOAuthCredentials) error {
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+
+	return os.Setenv(s.varName, base64.StdEncoding.EncodeToString(data))
+}
+
+func (s *EnvCredentialStore) Delete(ctx context.Context) error {
+	return os.Unsetenv(s.varName)
+}
+
+// MemoryCredentialStore keeps credentials in process memory. It never
+// touches disk or the environment, which makes it useful for tests and
+// short-lived programs that supply their own tokens.
+type MemoryCredentialStore struct {
+	mu    sync.RWMutex
+	creds *GeminiOAuthCredentials
+}
+
+// NewMemoryCredentialStore creates an empty in-memory store.
+func NewMemoryCredentialStore() *MemoryCredentialStore {
+	return &MemoryCredentialStore{}
+}
+
+func (s *MemoryCredentialStore) Load(ctx context.Context) (*GeminiOAuthCredentials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.creds == nil {
+		return nil, NewCredentialsNotFoundError("memory")
+	}
+
+	creds := *s.creds
+	return &creds, nil
+}
+
+func (s *MemoryCredentialStore) Save(ctx context.Context, creds *GeminiOAuthCredentials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *creds
+	s.creds = &stored
+	return nil
+}
+
+func (s *MemoryCredentialStore) Delete(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.creds = nil
+	return nil
+}
+
+// OSKeyringCredentialStore persists credentials in the operating system's
+// secure credential store (Keychain on macOS, Secret Service on Linux,
+// Credential Manager on Windows) via zalando/go-keyring, so desktop users
+// don't leave refresh tokens sitting in ~/.gemini in plaintext.
+type OSKeyringCredentialStore struct {
+	service string
+	user    string
+}
+
+// NewOSKeyringCredentialStore creates a store under the given service/user
+// pair. Empty values default to "geminicli-sdk" and "default".
+func NewOSKeyringCredentialStore(service, user string) *OSKeyringCredentialStore {
+	if service == "" {
+		service = "geminicli-sdk"
+	}
+	if user == "" {
+		user = "default"
+	}
+	return &OSKeyringCredentialStore{service: service, user: user}
+}
+
+func (s *OSKeyringCredentialStore) Load(ctx context.Context) (*GeminiOAuthCredentials, error) {
+	secret, err := keyring.Get(s.service, s.user)
+	if err != nil {
+		if err == keyring.ErrNotFound {
+			return nil, NewCredentialsNotFoundError(fmt.Sprintf("keyring:%s/%s", s.service, s.user))
+		}
+		return nil, err
+	}
+
+	var creds GeminiOAuthCredentials
+	if err := json.Unmarshal([]byte(secret), &creds); err != nil {
+		return nil, err
+	}
+
+	return &creds, nil
+}
+
+func (s *OSKeyringCredentialStore) Save(ctx context.Context, creds *GeminiOAuthCredentials) error {
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+
+	return keyring.Set(s.service, s.user, string(data))
+}
+
+func (s *OSKeyringCredentialStore) Delete(ctx context.Context) error {
+	if err := keyring.Delete(s.service, s.user); err != nil && err != keyring.ErrNotFound {
+		return err
+	}
+	return nil
+}
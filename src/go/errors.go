@@ -1,6 +1,24 @@
 package geminisdk
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors so callers can use errors.Is instead of type-switching on
+// concrete error types, e.g. errors.Is(err, geminisdk.ErrTokenExpired).
+var (
+	ErrCredentialsNotFound = errors.New("credentials not found")
+	ErrTokenExpired        = errors.New("token expired")
+	ErrTokenRefreshFailed  = errors.New("token refresh failed")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrQuotaExceeded       = errors.New("quota exceeded")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionClosed       = errors.New("session closed")
+	ErrToolNotFound        = errors.New("tool not found")
+	ErrCancelled           = errors.New("cancelled")
+	ErrTimeout             = errors.New("timeout")
+)
 
 // GeminiSDKError is the base error type
 type GeminiSDKError struct {
@@ -27,11 +45,28 @@ type CredentialsNotFoundError struct {
 	CredentialPath string
 }
 
+// Is reports whether target is ErrCredentialsNotFound.
+func (e *CredentialsNotFoundError) Is(target error) bool {
+	return target == ErrCredentialsNotFound
+}
+
 // TokenRefreshError represents token refresh failures
 type TokenRefreshError struct {
 	GeminiSDKError
 	StatusCode   int
 	ResponseBody string
+	Cause        error
+}
+
+// Is reports whether target is ErrTokenRefreshFailed.
+func (e *TokenRefreshError) Is(target error) bool {
+	return target == ErrTokenRefreshFailed
+}
+
+// Unwrap returns the underlying error that triggered the refresh failure,
+// if any (e.g. a network error from the token endpoint request).
+func (e *TokenRefreshError) Unwrap() error {
+	return e.Cause
 }
 
 // TokenExpiredError represents expired tokens
@@ -39,6 +74,11 @@ type TokenExpiredError struct {
 	GeminiSDKError
 }
 
+// Is reports whether target is ErrTokenExpired.
+func (e *TokenExpiredError) Is(target error) bool {
+	return target == ErrTokenExpired
+}
+
 // ConnectionError represents connection failures
 type ConnectionError struct {
 	GeminiSDKError
@@ -59,12 +99,22 @@ type RateLimitError struct {
 	RetryAfter int
 }
 
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
 // QuotaExceededError represents quota exhaustion
 type QuotaExceededError struct {
 	APIError
 	ResetTime string
 }
 
+// Is reports whether target is ErrQuotaExceeded.
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
 // PermissionDeniedError represents permission failures
 type PermissionDeniedError struct {
 	APIError
@@ -87,11 +137,21 @@ type SessionNotFoundError struct {
 	SessionError
 }
 
+// Is reports whether target is ErrSessionNotFound.
+func (e *SessionNotFoundError) Is(target error) bool {
+	return target == ErrSessionNotFound
+}
+
 // SessionClosedError represents closed sessions
 type SessionClosedError struct {
 	SessionError
 }
 
+// Is reports whether target is ErrSessionClosed.
+func (e *SessionClosedError) Is(target error) bool {
+	return target == ErrSessionClosed
+}
+
 // ToolError represents tool errors
 type ToolError struct {
 	GeminiSDKError
@@ -103,12 +163,22 @@ type ToolNotFoundError struct {
 	ToolError
 }
 
+// Is reports whether target is ErrToolNotFound.
+func (e *ToolNotFoundError) Is(target error) bool {
+	return target == ErrToolNotFound
+}
+
 // ToolExecutionError represents tool execution failures
 type ToolExecutionError struct {
 	ToolError
 	OriginalError error
 }
 
+// Unwrap returns the error raised by the tool handler itself.
+func (e *ToolExecutionError) Unwrap() error {
+	return e.OriginalError
+}
+
 // ValidationError represents validation failures
 type ValidationError struct {
 	GeminiSDKError
@@ -133,18 +203,51 @@ type CancellationError struct {
 	GeminiSDKError
 }
 
+// Is reports whether target is ErrCancelled.
+func (e *CancellationError) Is(target error) bool {
+	return target == ErrCancelled
+}
+
 // TimeoutError represents timeouts
 type TimeoutError struct {
 	GeminiSDKError
 	Timeout float64
 }
 
+// Is reports whether target is ErrTimeout.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
 // OnboardingError represents onboarding failures
 type OnboardingError struct {
 	GeminiSDKError
 	TierID string
 }
 
+// RetryExhaustedError is returned when Backend's retry policy gives up on a
+// transient error (429/5xx/network failure) after its configured maximum
+// number of attempts.
+type RetryExhaustedError struct {
+	GeminiSDKError
+	Attempts int
+	Cause    error
+}
+
+// Unwrap returns the last transient error that caused the retry policy to
+// give up.
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Cause
+}
+
+// SchemaValidationError represents a responseSchema validation failure:
+// the model's structured output didn't conform to the Schema the request
+// specified via GenerationConfig.ResponseSchema.
+type SchemaValidationError struct {
+	GeminiSDKError
+	Path string
+}
+
 // Helper functions to create errors
 
 // NewAuthenticationError creates a new authentication error
@@ -259,6 +362,19 @@ func NewToolExecutionError(toolName string, err error) *ToolExecutionError {
 	}
 }
 
+// NewValidationError creates a new validation error for field, whose value
+// was rejected before any request was sent.
+func NewValidationError(message, field string, value interface{}) *ValidationError {
+	return &ValidationError{
+		GeminiSDKError: GeminiSDKError{
+			Message: message,
+			Code:    "VALIDATION_ERROR",
+		},
+		Field: field,
+		Value: value,
+	}
+}
+
 // NewConfigurationError creates a new configuration error
 func NewConfigurationError(message string) *ConfigurationError {
 	return &ConfigurationError{
@@ -269,6 +385,16 @@ func NewConfigurationError(message string) *ConfigurationError {
 	}
 }
 
+// NewCancellationError creates a new cancellation error
+func NewCancellationError(message string) *CancellationError {
+	return &CancellationError{
+		GeminiSDKError: GeminiSDKError{
+			Message: message,
+			Code:    "CANCELLATION_ERROR",
+		},
+	}
+}
+
 // NewStreamError creates a new stream error
 func NewStreamError(message string) *StreamError {
 	return &StreamError{
@@ -279,6 +405,18 @@ func NewStreamError(message string) *StreamError {
 	}
 }
 
+// NewTimeoutError creates a new timeout error. timeoutSeconds is the
+// deadline that was exceeded.
+func NewTimeoutError(message string, timeoutSeconds float64) *TimeoutError {
+	return &TimeoutError{
+		GeminiSDKError: GeminiSDKError{
+			Message: message,
+			Code:    "TIMEOUT_ERROR",
+		},
+		Timeout: timeoutSeconds,
+	}
+}
+
 // NewOnboardingError creates a new onboarding error
 func NewOnboardingError(message string, tierID string) *OnboardingError {
 	return &OnboardingError{
@@ -289,3 +427,28 @@ func NewOnboardingError(message string, tierID string) *OnboardingError {
 		TierID: tierID,
 	}
 }
+
+// NewSchemaValidationError creates a new schema validation error. path is
+// the JSON path of the value that failed validation, e.g. "$.items[2].sku".
+func NewSchemaValidationError(message, path string) *SchemaValidationError {
+	return &SchemaValidationError{
+		GeminiSDKError: GeminiSDKError{
+			Message: fmt.Sprintf("%s: %s", path, message),
+			Code:    "SCHEMA_VALIDATION_ERROR",
+		},
+		Path: path,
+	}
+}
+
+// NewRetryExhaustedError creates a new retry exhausted error wrapping cause,
+// the error from the final attempt.
+func NewRetryExhaustedError(attempts int, cause error) *RetryExhaustedError {
+	return &RetryExhaustedError{
+		GeminiSDKError: GeminiSDKError{
+			Message: fmt.Sprintf("giving up after %d attempts: %v", attempts, cause),
+			Code:    "RETRY_EXHAUSTED",
+		},
+		Attempts: attempts,
+		Cause:    cause,
+	}
+}
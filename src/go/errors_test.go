@@ -0,0 +1,82 @@
+package geminisdk
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorsIsSentinels(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+	}{
+		{"CredentialsNotFoundError", NewCredentialsNotFoundError("/tmp/creds.json"), ErrCredentialsNotFound},
+		{"TokenRefreshError", NewTokenRefreshError("refresh failed", 401, ""), ErrTokenRefreshFailed},
+		{"RateLimitError", NewRateLimitError("rate limited", 30), ErrRateLimited},
+		{"QuotaExceededError", &QuotaExceededError{APIError: APIError{GeminiSDKError: GeminiSDKError{Message: "quota"}}}, ErrQuotaExceeded},
+		{"SessionNotFoundError", NewSessionNotFoundError("s1"), ErrSessionNotFound},
+		{"SessionClosedError", NewSessionClosedError("s1"), ErrSessionClosed},
+		{"ToolNotFoundError", NewToolNotFoundError("search"), ErrToolNotFound},
+		{"CancellationError", NewCancellationError("cancelled"), ErrCancelled},
+		{"TimeoutError", NewTimeoutError("timed out", 30), ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.target) {
+				t.Errorf("errors.Is(%T, %v) = false, want true", tt.err, tt.target)
+			}
+
+			wrapped := fmt.Errorf("wrapped: %w", tt.err)
+			if !errors.Is(wrapped, tt.target) {
+				t.Errorf("errors.Is(wrapped %T, %v) = false, want true", tt.err, tt.target)
+			}
+		})
+	}
+}
+
+func TestErrorsIsMismatch(t *testing.T) {
+	err := NewSessionNotFoundError("s1")
+	if errors.Is(err, ErrToolNotFound) {
+		t.Errorf("errors.Is(SessionNotFoundError, ErrToolNotFound) = true, want false")
+	}
+}
+
+func TestErrorsAsConcreteTypes(t *testing.T) {
+	err := NewToolExecutionError("search", ErrTimeout)
+
+	var toolErr *ToolExecutionError
+	if !errors.As(err, &toolErr) {
+		t.Fatalf("errors.As(%T, *ToolExecutionError) = false, want true", err)
+	}
+	if toolErr.ToolName != "search" {
+		t.Errorf("ToolName = %q, want %q", toolErr.ToolName, "search")
+	}
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("errors.Is(ToolExecutionError, ErrTimeout) = false, want true (via Unwrap of OriginalError)")
+	}
+}
+
+func TestErrorsAsRetryExhausted(t *testing.T) {
+	cause := NewAPIError("server error", 500, "/v1/complete")
+	err := NewRetryExhaustedError(3, cause)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("errors.As(%T, *APIError) = false, want true", err)
+	}
+	if apiErr.StatusCode != 500 {
+		t.Errorf("StatusCode = %d, want 500", apiErr.StatusCode)
+	}
+
+	var retryErr *RetryExhaustedError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("errors.As(%T, *RetryExhaustedError) = false, want true", err)
+	}
+	if retryErr.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", retryErr.Attempts)
+	}
+}
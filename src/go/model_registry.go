@@ -0,0 +1,307 @@
+package geminisdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// modelDiscoveryCacheFilename is where ModelRegistry persists its last
+// successful Refresh, inside GeminiDir (~/.gemini), alongside
+// oauth_creds.json and .env.
+const modelDiscoveryCacheFilename = "models.json"
+
+// ModelRegistry discovers the current Gemini model catalog from the Code
+// Assist endpoint, converting each entry into ModelInfo/ModelCapabilities
+// (including vision/tools/thinking support and prompt/context-window
+// limits) so callers can validate a request before sending it instead of
+// relying on the hardcoded GetGeminiCLIModels map, which drifts every time
+// a new preview model ships.
+//
+// A successful Refresh is cached to disk under ~/.gemini/models.json with
+// an ETag and fetch timestamp, the same way OAuthManager persists
+// credentials; the next process starts from that cache instead of the
+// hardcoded map. If Refresh has never succeeded and no cache exists,
+// ModelRegistry serves GetGeminiCLIModels/GetGeminiCLIEmbeddingModels.
+type ModelRegistry struct {
+	backend   *Backend
+	cachePath string
+
+	mu      sync.RWMutex
+	models  map[string]ModelInfo
+	etag    string
+	fetched time.Time
+}
+
+// modelRegistryCache is the on-disk shape of ~/.gemini/models.json.
+type modelRegistryCache struct {
+	ETag      string               `json:"etag,omitempty"`
+	FetchedAt time.Time            `json:"fetched_at"`
+	Models    map[string]ModelInfo `json:"models"`
+}
+
+// NewModelRegistry creates a registry backed by b that discovers models
+// through b's OAuth credentials. customCachePath overrides the disk cache
+// location; empty uses ~/.gemini/models.json. The registry starts out
+// populated from the hardcoded catalog (or the disk cache, if one exists
+// from a prior Refresh); call Refresh to query the discovery endpoint.
+func NewModelRegistry(b *Backend, customCachePath string) *ModelRegistry {
+	cachePath := customCachePath
+	if cachePath == "" {
+		homeDir, _ := os.UserHomeDir()
+		cachePath = filepath.Join(homeDir, GeminiDir, modelDiscoveryCacheFilename)
+	}
+
+	r := &ModelRegistry{
+		backend:   b,
+		cachePath: cachePath,
+		models:    hardcodedModelCatalog(),
+	}
+	r.loadCache()
+	return r
+}
+
+// hardcodedModelCatalog converts GetGeminiCLIModels/GetGeminiCLIEmbeddingModels
+// into ModelInfo, used as ModelRegistry's seed and its fallback once
+// Refresh fails.
+func hardcodedModelCatalog() map[string]ModelInfo {
+	out := make(map[string]ModelInfo)
+	for id, info := range GetGeminiCLIModels() {
+		out[id] = modelInfoFromLegacy(info)
+	}
+	for id, info := range GetGeminiCLIEmbeddingModels() {
+		out[id] = modelInfoFromLegacy(info)
+	}
+	return out
+}
+
+func modelInfoFromLegacy(info GeminiModelInfo) ModelInfo {
+	contextWindow := info.ContextWindow
+	return ModelInfo{
+		ID:   info.ID,
+		Name: info.Name,
+		Capabilities: ModelCapabilities{
+			Supports: ModelSupports{
+				Tools:    info.SupportsNativeTools,
+				Thinking: info.SupportsThinking,
+			},
+			Limits: ModelLimits{
+				MaxPromptTokens:        &contextWindow,
+				MaxContextWindowTokens: &contextWindow,
+			},
+		},
+	}
+}
+
+// loadCache populates r from cachePath, leaving the hardcoded catalog in
+// place if the file is missing, unreadable, or empty.
+func (r *ModelRegistry) loadCache() {
+	data, err := os.ReadFile(r.cachePath)
+	if err != nil {
+		return
+	}
+	var cache modelRegistryCache
+	if err := json.Unmarshal(data, &cache); err != nil || len(cache.Models) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.models = cache.Models
+	r.etag = cache.ETag
+	r.fetched = cache.FetchedAt
+}
+
+func (r *ModelRegistry) saveCache() error {
+	r.mu.RLock()
+	cache := modelRegistryCache{ETag: r.etag, FetchedAt: r.fetched, Models: r.models}
+	r.mu.RUnlock()
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.cachePath), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(r.cachePath, data, 0o600)
+}
+
+// Refresh queries the Code Assist discovery endpoint for the current model
+// catalog, replacing the in-memory catalog and the on-disk cache on
+// success. It sends the last ETag via If-None-Match, so an unchanged
+// catalog only costs a 304. On failure, Refresh returns the error and
+// leaves the registry serving its last good catalog — the disk cache if
+// one was loaded, otherwise the hardcoded maps.
+func (r *ModelRegistry) Refresh(ctx context.Context) error {
+	var result *modelDiscoveryResult
+	err := r.backend.retryLoop(ctx, func(forceRefresh bool) attemptOutcome {
+		res, outcome := r.refreshAttempt(ctx, forceRefresh)
+		if outcome.err == nil {
+			result = res
+		}
+		return outcome
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.fetched = time.Now()
+	if !result.notModified {
+		r.models = result.models
+		r.etag = result.etag
+	}
+	r.mu.Unlock()
+
+	return r.saveCache()
+}
+
+// modelDiscoveryResult is a successfully-parsed discovery response, or a
+// 304 Not Modified marker telling Refresh to keep the current catalog.
+type modelDiscoveryResult struct {
+	models      map[string]ModelInfo
+	etag        string
+	notModified bool
+}
+
+func (r *ModelRegistry) refreshAttempt(ctx context.Context, forceRefresh bool) (*modelDiscoveryResult, attemptOutcome) {
+	headers, err := r.backend.getAuthHeaders(ctx, forceRefresh)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	url := fmt.Sprintf("%s:listModels", r.backend.oauthManager.GetAPIEndpoint())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	r.mu.RLock()
+	etag := r.etag
+	r.mu.RUnlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := r.backend.doWithAttemptTimeout(req)
+	if err != nil {
+		return nil, attemptOutcome{err: err, transient: isRetryableTransportError(err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &modelDiscoveryResult{notModified: true}, attemptOutcome{}
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, attemptOutcome{err: r.backend.handleHTTPError(resp.StatusCode, string(body)), authRetry: true}
+	}
+	if isRetryableStatusCode(resp.StatusCode) {
+		return nil, attemptOutcome{
+			err:        r.backend.handleHTTPError(resp.StatusCode, string(body)),
+			transient:  true,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, attemptOutcome{err: r.backend.handleHTTPError(resp.StatusCode, string(body))}
+	}
+
+	var data struct {
+		Models []modelDescriptor `json:"models"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, attemptOutcome{err: err}
+	}
+
+	models := make(map[string]ModelInfo, len(data.Models))
+	for _, d := range data.Models {
+		info := d.toModelInfo()
+		models[info.ID] = info
+	}
+
+	return &modelDiscoveryResult{models: models, etag: resp.Header.Get("ETag")}, attemptOutcome{}
+}
+
+// modelDescriptor is the discovery endpoint's JSON shape for a single
+// model, converted into ModelInfo by toModelInfo.
+type modelDescriptor struct {
+	Name                   string   `json:"name"`
+	DisplayName            string   `json:"displayName"`
+	SupportsVision         bool     `json:"supportsVision"`
+	SupportsTools          bool     `json:"supportsTools"`
+	SupportsThinking       bool     `json:"supportsThinking"`
+	MaxPromptTokens        *int64   `json:"maxPromptTokens,omitempty"`
+	MaxContextWindowTokens *int64   `json:"maxContextWindowTokens,omitempty"`
+	SupportedMediaTypes    []string `json:"supportedMediaTypes,omitempty"`
+	MaxPromptImages        int      `json:"maxPromptImages,omitempty"`
+	MaxPromptImageSize     int64    `json:"maxPromptImageSize,omitempty"`
+}
+
+func (d modelDescriptor) toModelInfo() ModelInfo {
+	var visionLimits *ModelVisionLimits
+	if len(d.SupportedMediaTypes) > 0 || d.MaxPromptImages > 0 || d.MaxPromptImageSize > 0 {
+		visionLimits = &ModelVisionLimits{
+			SupportedMediaTypes: d.SupportedMediaTypes,
+			MaxPromptImages:     d.MaxPromptImages,
+			MaxPromptImageSize:  d.MaxPromptImageSize,
+		}
+	}
+
+	return ModelInfo{
+		ID:   d.Name,
+		Name: d.DisplayName,
+		Capabilities: ModelCapabilities{
+			Supports: ModelSupports{
+				Vision:   d.SupportsVision,
+				Tools:    d.SupportsTools,
+				Thinking: d.SupportsThinking,
+			},
+			Limits: ModelLimits{
+				MaxPromptTokens:        d.MaxPromptTokens,
+				MaxContextWindowTokens: d.MaxContextWindowTokens,
+				Vision:                 visionLimits,
+			},
+		},
+	}
+}
+
+// Lookup returns the catalog entry for id, as of the last successful
+// Refresh (or the hardcoded/cached catalog if Refresh has never run).
+func (r *ModelRegistry) Lookup(id string) (ModelInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.models[id]
+	return info, ok
+}
+
+// SupportsCapability reports whether model id supports cap, returning
+// false if id isn't in the catalog.
+func (r *ModelRegistry) SupportsCapability(id string, cap ModelCapability) bool {
+	info, ok := r.Lookup(id)
+	if !ok {
+		return false
+	}
+	switch cap {
+	case CapabilityVision:
+		return info.Capabilities.Supports.Vision
+	case CapabilityTools:
+		return info.Capabilities.Supports.Tools
+	case CapabilityThinking:
+		return info.Capabilities.Supports.Thinking
+	default:
+		return false
+	}
+}
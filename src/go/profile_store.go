@@ -0,0 +1,170 @@
+package geminisdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// profileStoreFile is the on-disk shape ProfileCredentialStore reads and
+// writes: a JSON object keyed by profile label, instead of
+// FileCredentialStore's single flat credentials object.
+type profileStoreFile struct {
+	Active   string                             `json:"active,omitempty"`
+	Profiles map[string]*GeminiOAuthCredentials `json:"profiles"`
+}
+
+// ProfileCredentialStore persists OAuth credentials for multiple named
+// accounts ("profiles" — personal, workspace, a service account, ...) in a
+// single JSON file keyed by label, instead of FileCredentialStore's one
+// account per path. It implements CredentialStore itself against whichever
+// profile is currently Active, so it drops straight into OAuthManager via
+// WithCredentialStore; Use and RotateOnQuotaError change which profile
+// that is. Each profile's ExpiryDate is honored the same way
+// OAuthManager.isTokenValid already honors TokenRefreshBufferMs for a
+// single account — ProfileCredentialStore only changes which account's
+// credentials Load/Save see.
+type ProfileCredentialStore struct {
+	path string
+
+	mu       sync.RWMutex
+	active   string
+	order    []string
+	profiles map[string]*GeminiOAuthCredentials
+}
+
+// NewProfileCredentialStore creates a store rooted at path (empty resolves
+// to the default Gemini CLI credential path, same as
+// NewFileCredentialStore), rotating through labels in the given order when
+// RotateOnQuotaError is called. Any profiles already saved at path are
+// loaded; Active defaults to the first label in order until Use or a
+// prior Save picks one.
+func NewProfileCredentialStore(path string, labels []string) *ProfileCredentialStore {
+	s := &ProfileCredentialStore{
+		path:     GetGeminiCLICredentialPath(path),
+		order:    append([]string(nil), labels...),
+		profiles: make(map[string]*GeminiOAuthCredentials),
+	}
+	s.load()
+	if s.active == "" && len(s.order) > 0 {
+		s.active = s.order[0]
+	}
+	return s
+}
+
+func (s *ProfileCredentialStore) load() {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return
+	}
+	var file profileStoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return
+	}
+	s.active = file.Active
+	if file.Profiles != nil {
+		s.profiles = file.Profiles
+	}
+}
+
+func (s *ProfileCredentialStore) persist() error {
+	file := profileStoreFile{Active: s.active, Profiles: s.profiles}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
+
+// Load returns the active profile's credentials, implementing
+// CredentialStore for OAuthManager/Backend.
+func (s *ProfileCredentialStore) Load(ctx context.Context) (*GeminiOAuthCredentials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	creds, ok := s.profiles[s.active]
+	if !ok || creds == nil {
+		return nil, NewCredentialsNotFoundError(fmt.Sprintf("%s (profile %q)", s.path, s.active))
+	}
+	stored := *creds
+	return &stored, nil
+}
+
+// Save persists creds under the active profile.
+func (s *ProfileCredentialStore) Save(ctx context.Context, creds *GeminiOAuthCredentials) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *creds
+	s.profiles[s.active] = &stored
+	return s.persist()
+}
+
+// Delete removes the active profile's credentials, leaving the others
+// untouched.
+func (s *ProfileCredentialStore) Delete(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.profiles, s.active)
+	return s.persist()
+}
+
+// Active returns the currently active profile's label.
+func (s *ProfileCredentialStore) Active() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active
+}
+
+// Use switches the active profile to label, which must appear in the
+// rotation order passed to NewProfileCredentialStore.
+func (s *ProfileCredentialStore) Use(label string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if indexOfLabel(s.order, label) < 0 {
+		return NewValidationError(fmt.Sprintf("unknown profile %q", label), "profile", label)
+	}
+	s.active = label
+	return s.persist()
+}
+
+// RotateOnQuotaError inspects err and, if it indicates quota exhaustion or
+// rate limiting (errors.Is ErrQuotaExceeded or ErrRateLimited), advances
+// Active to the next profile in round-robin order. It reports whether it
+// rotated — false if err isn't a quota error, or no other profile is
+// configured to rotate to.
+func (s *ProfileCredentialStore) RotateOnQuotaError(err error) bool {
+	if !errors.Is(err, ErrQuotaExceeded) && !errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.order) < 2 {
+		return false
+	}
+
+	idx := indexOfLabel(s.order, s.active)
+	next := s.order[(idx+1)%len(s.order)]
+	if next == s.active {
+		return false
+	}
+	s.active = next
+	_ = s.persist()
+	return true
+}
+
+func indexOfLabel(labels []string, label string) int {
+	for i, l := range labels {
+		if l == label {
+			return i
+		}
+	}
+	return -1
+}
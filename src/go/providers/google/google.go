@@ -0,0 +1,417 @@
+// Package google implements a geminisdk.ChatCompletionProvider backed by
+// the Google Generative Language REST API (https://ai.google.dev), as an
+// alternative to the Gemini CLI OAuth backend in the root package. It
+// authenticates with a plain API key instead of OAuth credentials, and
+// talks to v1beta/models/{model}:generateContent directly rather than
+// going through the Code Assist endpoint.
+package google
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	geminisdk "github.com/OEvortex/geminicli-sdk/go"
+)
+
+const defaultBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// Options configures a Provider.
+type Options struct {
+	// APIKey authenticates requests via the x-goog-api-key header, kept out
+	// of the request URL so it can't leak into logs of transport errors or
+	// access logs on an intermediate proxy.
+	APIKey string
+	// BaseURL overrides the API host, defaulting to defaultBaseURL. Useful
+	// for testing against a mock server.
+	BaseURL string
+	Timeout time.Duration
+}
+
+// Provider talks to the Generative Language REST API. It implements
+// geminisdk.ChatCompletionProvider, so it can be passed to
+// geminisdk.NewClientWithProvider in place of the default *geminisdk.Backend.
+type Provider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewProvider creates a Provider authenticated with opts.APIKey.
+func NewProvider(opts *Options) *Provider {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 720 * time.Second
+	}
+
+	return &Provider{
+		apiKey:  opts.APIKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// prepareContents splits messages into the REST API's contents list and its
+// systemInstructions parts, using the Generative Language API's own field
+// names. Unlike Backend.prepareMessages, it sends only text and ImageData
+// parts; ContentPart.Data and ContentPart.FileURI are not sent.
+func (p *Provider) prepareContents(messages []geminisdk.Message) (contents []map[string]interface{}, systemParts []map[string]interface{}) {
+	for _, msg := range messages {
+		var parts []map[string]interface{}
+
+		if msg.Content != "" {
+			parts = append(parts, map[string]interface{}{"text": msg.Content})
+		}
+
+		for _, part := range msg.Parts {
+			if part.Text != "" {
+				parts = append(parts, map[string]interface{}{"text": part.Text})
+			}
+			if len(part.ImageData) > 0 && part.ImageMimeType != "" {
+				parts = append(parts, map[string]interface{}{
+					"inlineData": map[string]interface{}{
+						"mimeType": part.ImageMimeType,
+						"data":     base64.StdEncoding.EncodeToString(part.ImageData),
+					},
+				})
+			}
+		}
+
+		for _, tc := range msg.ToolCalls {
+			var args interface{}
+			if err := json.Unmarshal(tc.Function.Arguments, &args); err != nil {
+				args = map[string]interface{}{}
+			}
+			parts = append(parts, map[string]interface{}{
+				"functionCall": map[string]interface{}{
+					"name": tc.Function.Name,
+					"args": args,
+				},
+			})
+		}
+
+		if msg.ToolCallID != "" {
+			parts = append(parts, map[string]interface{}{
+				"functionResponse": map[string]interface{}{
+					"name": msg.Name,
+					"response": map[string]interface{}{
+						"result": msg.Content,
+					},
+				},
+			})
+		}
+
+		if len(parts) == 0 {
+			continue
+		}
+
+		if msg.Role == geminisdk.RoleSystem {
+			systemParts = append(systemParts, parts...)
+			continue
+		}
+
+		role := "user"
+		if msg.Role == geminisdk.RoleAssistant {
+			role = "model"
+		}
+		contents = append(contents, map[string]interface{}{
+			"role":  role,
+			"parts": parts,
+		})
+	}
+
+	return contents, systemParts
+}
+
+func (p *Provider) prepareTools(tools []geminisdk.Tool) []map[string]interface{} {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	var funcDecls []map[string]interface{}
+	for _, tool := range tools {
+		funcDef := map[string]interface{}{
+			"name":        tool.Name,
+			"description": tool.Description,
+		}
+
+		if len(tool.Parameters) > 0 {
+			var params map[string]interface{}
+			if err := json.Unmarshal(tool.Parameters, &params); err == nil {
+				funcDef["parameters"] = map[string]interface{}{
+					"type":       "object",
+					"properties": params["properties"],
+					"required":   params["required"],
+				}
+			}
+		}
+
+		funcDecls = append(funcDecls, funcDef)
+	}
+
+	return []map[string]interface{}{
+		{"functionDeclarations": funcDecls},
+	}
+}
+
+// buildRequest assembles a GenerateContentRequest body: contents, tools,
+// systemInstructions, and generationConfig{maxOutputTokens,temperature,topP,topK}.
+func (p *Provider) buildRequest(
+	messages []geminisdk.Message,
+	generationConfig *geminisdk.GenerationConfig,
+	tools []geminisdk.Tool,
+) map[string]interface{} {
+	contents, systemParts := p.prepareContents(messages)
+
+	genConfig := map[string]interface{}{}
+	if generationConfig != nil {
+		if generationConfig.Temperature != 0 {
+			genConfig["temperature"] = generationConfig.Temperature
+		}
+		if generationConfig.MaxOutputTokens > 0 {
+			genConfig["maxOutputTokens"] = generationConfig.MaxOutputTokens
+		}
+		if generationConfig.TopP > 0 {
+			genConfig["topP"] = generationConfig.TopP
+		}
+		if generationConfig.TopK > 0 {
+			genConfig["topK"] = generationConfig.TopK
+		}
+		if len(generationConfig.StopSequences) > 0 {
+			genConfig["stopSequences"] = generationConfig.StopSequences
+		}
+	}
+
+	body := map[string]interface{}{
+		"contents": contents,
+	}
+	if len(genConfig) > 0 {
+		body["generationConfig"] = genConfig
+	}
+	if len(systemParts) > 0 {
+		body["systemInstructions"] = map[string]interface{}{"parts": systemParts}
+	}
+	if preparedTools := p.prepareTools(tools); preparedTools != nil {
+		body["tools"] = preparedTools
+	}
+
+	return body
+}
+
+// parseResponse parses a candidates[].content.parts[] / usageMetadata
+// response body into an LLMChunk, the same shape Backend returns.
+func (p *Provider) parseResponse(data map[string]interface{}) *geminisdk.LLMChunk {
+	candidates, _ := data["candidates"].([]interface{})
+	if len(candidates) == 0 {
+		return &geminisdk.LLMChunk{}
+	}
+
+	candidate, _ := candidates[0].(map[string]interface{})
+	content, _ := candidate["content"].(map[string]interface{})
+	parts, _ := content["parts"].([]interface{})
+
+	var textContent string
+	var toolCalls []geminisdk.ToolCall
+
+	for _, rawPart := range parts {
+		part, ok := rawPart.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if text, ok := part["text"].(string); ok {
+			textContent += text
+		}
+		if fc, ok := part["functionCall"].(map[string]interface{}); ok {
+			name, _ := fc["name"].(string)
+			argsJSON, _ := json.Marshal(fc["args"])
+
+			toolCalls = append(toolCalls, geminisdk.ToolCall{
+				ID:   uuid.New().String(),
+				Type: "function",
+				Function: geminisdk.FunctionCall{
+					Name:      name,
+					Arguments: argsJSON,
+				},
+			})
+		}
+	}
+
+	var usage *geminisdk.LLMUsage
+	if u, ok := data["usageMetadata"].(map[string]interface{}); ok {
+		usage = &geminisdk.LLMUsage{
+			PromptTokens:     int64(getFloat(u, "promptTokenCount")),
+			CompletionTokens: int64(getFloat(u, "candidatesTokenCount")),
+			TotalTokens:      int64(getFloat(u, "totalTokenCount")),
+		}
+	}
+
+	finishReason, _ := candidate["finishReason"].(string)
+
+	return &geminisdk.LLMChunk{
+		Content:      textContent,
+		ToolCalls:    toolCalls,
+		Usage:        usage,
+		FinishReason: finishReason,
+	}
+}
+
+func getFloat(m map[string]interface{}, key string) float64 {
+	if v, ok := m[key].(float64); ok {
+		return v
+	}
+	return 0
+}
+
+func (p *Provider) endpoint(model, method string) string {
+	return fmt.Sprintf("%s/models/%s:%s", p.baseURL, model, method)
+}
+
+// Complete performs a non-streaming completion against generateContent.
+func (p *Provider) Complete(
+	ctx context.Context,
+	model string,
+	messages []geminisdk.Message,
+	generationConfig *geminisdk.GenerationConfig,
+	_ *geminisdk.ThinkingConfig,
+	tools []geminisdk.Tool,
+) (*geminisdk.LLMChunk, error) {
+	payload := p.buildRequest(messages, generationConfig, tools)
+	reqBody, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint(model, "generateContent"), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", p.apiKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.handleHTTPError(resp.StatusCode, string(body))
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+
+	return p.parseResponse(data), nil
+}
+
+// CompleteStreaming performs a streaming completion against
+// streamGenerateContent, parsing the same SSE `data:` framing the Gemini CLI
+// backend uses.
+func (p *Provider) CompleteStreaming(
+	ctx context.Context,
+	model string,
+	messages []geminisdk.Message,
+	generationConfig *geminisdk.GenerationConfig,
+	_ *geminisdk.ThinkingConfig,
+	tools []geminisdk.Tool,
+) (geminisdk.ChunkChannel, error) {
+	payload := p.buildRequest(messages, generationConfig, tools)
+	reqBody, _ := json.Marshal(payload)
+
+	url := p.endpoint(model, "streamGenerateContent") + "?alt=sse"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", p.apiKey)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, p.handleHTTPError(resp.StatusCode, string(body))
+	}
+
+	ch := make(chan geminisdk.StreamResult, 100)
+
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+		for {
+			raw, readErr := geminisdk.ReadLineWithLimit(reader, geminisdk.DefaultMaxLineSize)
+
+			line := strings.TrimSpace(raw)
+			if line != "" && !strings.HasPrefix(line, ":") && strings.HasPrefix(line, "data:") {
+				data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+				if data != "[DONE]" {
+					var parsed map[string]interface{}
+					if err := json.Unmarshal([]byte(data), &parsed); err == nil {
+						ch <- geminisdk.StreamResult{Chunk: p.parseResponse(parsed)}
+					}
+				}
+			}
+
+			if readErr != nil {
+				if readErr != io.EOF {
+					ch <- geminisdk.StreamResult{Error: geminisdk.NewStreamError(readErr.Error())}
+				}
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+func (p *Provider) handleHTTPError(statusCode int, body string) error {
+	var errorMsg string
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &data); err == nil {
+		if errObj, ok := data["error"].(map[string]interface{}); ok {
+			if msg, ok := errObj["message"].(string); ok {
+				errorMsg = msg
+			}
+		}
+	}
+	if errorMsg == "" {
+		errorMsg = body
+	}
+
+	switch statusCode {
+	case 429:
+		return geminisdk.NewRateLimitError(fmt.Sprintf("Rate limit exceeded: %s", errorMsg), 0)
+	case 403:
+		return geminisdk.NewAPIError(fmt.Sprintf("Permission denied: %s", errorMsg), statusCode, "")
+	default:
+		return geminisdk.NewAPIError(fmt.Sprintf("API error: %s", errorMsg), statusCode, "")
+	}
+}
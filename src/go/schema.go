@@ -0,0 +1,223 @@
+package geminisdk
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// validateSchema checks value (as decoded by encoding/json, so objects are
+// map[string]interface{} and numbers are float64) against schema, returning
+// a *SchemaValidationError at the first mismatch. It implements the subset
+// of JSON Schema that Schema exposes (type, properties/required, items,
+// enum, nullable) — enough to catch a model ignoring responseSchema, not a
+// general-purpose JSON Schema validator.
+func validateSchema(schema *Schema, value interface{}, path string) error {
+	if schema == nil {
+		return nil
+	}
+
+	if value == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return NewSchemaValidationError(fmt.Sprintf("expected %s, got null", schema.Type), path)
+	}
+
+	switch schema.Type {
+	case SchemaTypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return NewSchemaValidationError(fmt.Sprintf("expected object, got %T", value), path)
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				return NewSchemaValidationError(fmt.Sprintf("missing required property %q", name), path)
+			}
+		}
+		for name, propSchema := range schema.Properties {
+			propValue, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if err := validateSchema(propSchema, propValue, path+"."+name); err != nil {
+				return err
+			}
+		}
+
+	case SchemaTypeArray:
+		arr, ok := value.([]interface{})
+		if !ok {
+			return NewSchemaValidationError(fmt.Sprintf("expected array, got %T", value), path)
+		}
+		if schema.Items != nil {
+			for i, item := range arr {
+				if err := validateSchema(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case SchemaTypeString:
+		str, ok := value.(string)
+		if !ok {
+			return NewSchemaValidationError(fmt.Sprintf("expected string, got %T", value), path)
+		}
+		if len(schema.Enum) > 0 && !containsString(schema.Enum, str) {
+			return NewSchemaValidationError(fmt.Sprintf("value %q is not one of the allowed enum values", str), path)
+		}
+
+	case SchemaTypeNumber, SchemaTypeInteger:
+		if _, ok := value.(float64); !ok {
+			return NewSchemaValidationError(fmt.Sprintf("expected number, got %T", value), path)
+		}
+
+	case SchemaTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return NewSchemaValidationError(fmt.Sprintf("expected boolean, got %T", value), path)
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseSchemaFromGo reflects v (a struct, or pointer to one) into a
+// *Schema suitable for GenerationConfig.ResponseSchema, so callers don't
+// have to hand-write one that mirrors a Go type they already have. Each
+// field's `json` tag controls its property name; a field is Required unless
+// its tag carries `omitempty` or it is a pointer, slice, or map. A
+// `gemini:"enum=A|B|C"` tag turns a string field
+// into a Schema with Enum set. Nested structs, pointers, and slices are
+// reflected recursively; maps become an untyped OBJECT, since Gemini's
+// responseSchema has no concept of an open-ended property set. It returns
+// an error, rather than panicking, if v contains a field of a kind with no
+// JSON Schema equivalent (chan, func, unsafe pointer, interface{}).
+func ResponseSchemaFromGo(v any) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return schemaFromType(t)
+}
+
+// textMarshalerType and jsonMarshalerType let schemaFromType special-case
+// types (like time.Time) that marshal themselves to a JSON string instead
+// of reflecting into their private fields, which would otherwise produce a
+// bogus empty-object schema that rejects the model's actual output.
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+func schemaFromType(t reflect.Type) (*Schema, error) {
+	if t == reflect.TypeOf(time.Time{}) || t.Implements(textMarshalerType) || t.Implements(jsonMarshalerType) ||
+		reflect.PtrTo(t).Implements(textMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return &Schema{Type: SchemaTypeString}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema, err := schemaFromType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		schema.Nullable = true
+		return schema, nil
+	case reflect.String:
+		return &Schema{Type: SchemaTypeString}, nil
+	case reflect.Bool:
+		return &Schema{Type: SchemaTypeBoolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: SchemaTypeInteger}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaTypeNumber}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := schemaFromType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: SchemaTypeArray, Items: items}, nil
+	case reflect.Map:
+		return &Schema{Type: SchemaTypeObject}, nil
+	case reflect.Struct:
+		return schemaFromStruct(t)
+	default:
+		return nil, fmt.Errorf("ResponseSchemaFromGo: unsupported field kind %s", t.Kind())
+	}
+}
+
+func schemaFromStruct(t reflect.Type) (*Schema, error) {
+	schema := &Schema{Type: SchemaTypeObject, Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name, omitempty := parseJSONTag(field.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		propSchema, err := schemaFromType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field.Name, err)
+		}
+		if values := parseEnumTag(field.Tag.Get("gemini")); len(values) > 0 {
+			propSchema.Type = SchemaTypeString
+			propSchema.Enum = values
+		}
+
+		schema.Properties[name] = propSchema
+		schema.PropertyOrdering = append(schema.PropertyOrdering, name)
+
+		optional := omitempty || field.Type.Kind() == reflect.Ptr ||
+			field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Map
+		if !optional {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema, nil
+}
+
+// parseJSONTag splits a struct field's `json` tag into its property name
+// (empty if the tag itself is empty, meaning "use the field name") and
+// whether it carries the omitempty option.
+func parseJSONTag(tag string) (name string, omitempty bool) {
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// parseEnumTag parses a `gemini:"enum=A|B|C"` tag into its allowed values,
+// or nil if tag doesn't start with "enum=".
+func parseEnumTag(tag string) []string {
+	const prefix = "enum="
+	if !strings.HasPrefix(tag, prefix) {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(tag, prefix), "|")
+}
@@ -0,0 +1,36 @@
+package geminisdk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResponseSchemaFromGoTimeField(t *testing.T) {
+	type Event struct {
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	schema, err := ResponseSchemaFromGo(Event{})
+	if err != nil {
+		t.Fatalf("ResponseSchemaFromGo: %v", err)
+	}
+
+	created, ok := schema.Properties["created_at"]
+	if !ok {
+		t.Fatalf("schema has no created_at property: %+v", schema.Properties)
+	}
+	if created.Type != SchemaTypeString {
+		t.Errorf("created_at schema type = %s, want %s", created.Type, SchemaTypeString)
+	}
+}
+
+func TestResponseSchemaFromGoUnsupportedKind(t *testing.T) {
+	type Bad struct {
+		Callback func() `json:"callback"`
+	}
+
+	if _, err := ResponseSchemaFromGo(Bad{}); err == nil {
+		t.Fatal("ResponseSchemaFromGo(Bad{}) = nil error, want error for unsupported field kind")
+	}
+}
@@ -3,6 +3,7 @@ package geminisdk
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -20,27 +21,33 @@ type EventHandler func(event SessionEvent)
 type Session struct {
 	sessionID        string
 	model            string
-	backend          *Backend
+	backend          ChatCompletionProvider
 	tools            []Tool
 	toolHandlers     map[string]ToolHandler
 	systemMessage    string
 	generationConfig *GenerationConfig
 	thinkingConfig   *ThinkingConfig
 	streaming        bool
-
-	messages      []Message
-	eventHandlers []EventHandler
-	closed        bool
-	startTime     time.Time
-	modifiedTime  time.Time
-	mu            sync.RWMutex
+	approvalPolicy   ApprovalPolicy
+	manualToolDispatch bool
+	store            SessionStore
+
+	messages         []Message
+	pendingToolCalls []ToolCall
+	eventHandlers    []EventHandler
+	closed           bool
+	startTime        time.Time
+	modifiedTime     time.Time
+	parentSessionID  string
+	forkPoint        int
+	mu               sync.RWMutex
 }
 
 // NewSession creates a new session
 func NewSession(
 	sessionID string,
 	model string,
-	backend *Backend,
+	backend ChatCompletionProvider,
 	tools []Tool,
 	systemMessage string,
 	generationConfig *GenerationConfig,
@@ -113,6 +120,79 @@ func (s *Session) RegisterToolHandler(name string, handler ToolHandler) {
 	s.toolHandlers[name] = handler
 }
 
+// SetApprovalPolicy installs the policy consulted before each pending tool
+// call is dispatched to its handler. Pass nil to restore the default
+// auto-approve behavior.
+func (s *Session) SetApprovalPolicy(policy ApprovalPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.approvalPolicy = policy
+}
+
+// SetManualToolDispatch enables or disables manual tool dispatch. When
+// enabled, a model reply containing tool calls is not run through the
+// registered handlers: Send/SendAndWait return as soon as the assistant's
+// message (carrying the ToolCalls) is available, and the caller must
+// execute them itself and report back via SubmitToolResults.
+func (s *Session) SetManualToolDispatch(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.manualToolDispatch = enabled
+}
+
+// SetSessionStore installs the store used to persist history incrementally
+// as messages are added, via AppendMessage. This is independent of any
+// auto-persist-on-idle snapshot wiring a Client sets up on top of the same
+// store.
+func (s *Session) SetSessionStore(store SessionStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+}
+
+// recordMessage mirrors a message that was just appended to s.messages to
+// the configured SessionStore, if any. Call it after releasing s.mu.
+func (s *Session) recordMessage(msg Message) {
+	s.mu.RLock()
+	store := s.store
+	sessionID := s.sessionID
+	s.mu.RUnlock()
+
+	if store == nil {
+		return
+	}
+
+	if err := store.AppendMessage(context.Background(), sessionID, msg); err != nil {
+		fmt.Printf("Warning: Failed to persist message: %v\n", err)
+	}
+}
+
+// PendingToolCalls returns the tool calls awaiting SubmitToolResults, or
+// nil if there are none outstanding.
+func (s *Session) PendingToolCalls() []ToolCall {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]ToolCall, len(s.pendingToolCalls))
+	copy(result, s.pendingToolCalls)
+	return result
+}
+
+// ParentSessionID returns the session ID this session was forked from, or
+// "" if it was not created via Fork.
+func (s *Session) ParentSessionID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parentSessionID
+}
+
+// ForkPoint returns the message index this session branched from its
+// parent at. Only meaningful when ParentSessionID is non-empty.
+func (s *Session) ForkPoint() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.forkPoint
+}
+
 // On registers an event handler
 func (s *Session) On(handler EventHandler) {
 	s.mu.Lock()
@@ -137,6 +217,22 @@ func (s *Session) emit(eventType EventType, data map[string]interface{}) {
 	}
 }
 
+// emitSessionError emits the most specific EventSessionError variant err
+// matches — EventSessionQuotaExceeded for quota/rate-limit errors,
+// EventSessionAuthFailed for credential failures — falling back to the
+// generic EventSessionError, so a listener (e.g. Client's multi-profile
+// rotation) can react to a quota error without string-matching err.Error().
+func (s *Session) emitSessionError(err error) {
+	eventType := EventSessionError
+	switch {
+	case errors.Is(err, ErrQuotaExceeded), errors.Is(err, ErrRateLimited):
+		eventType = EventSessionQuotaExceeded
+	case errors.Is(err, ErrTokenExpired), errors.Is(err, ErrTokenRefreshFailed), errors.Is(err, ErrCredentialsNotFound):
+		eventType = EventSessionAuthFailed
+	}
+	s.emit(eventType, map[string]interface{}{"error": err.Error()})
+}
+
 // Send sends a message to the session
 func (s *Session) Send(ctx context.Context, options *MessageOptions) error {
 	s.mu.RLock()
@@ -160,6 +256,10 @@ func (s *Session) Send(ctx context.Context, options *MessageOptions) error {
 	s.messages = append(s.messages, userMessage)
 	s.modifiedTime = time.Now()
 	s.mu.Unlock()
+	s.recordMessage(userMessage)
+	s.emit(EventUserMessage, map[string]interface{}{
+		"content": content,
+	})
 
 	var err error
 	if s.streaming {
@@ -169,7 +269,7 @@ func (s *Session) Send(ctx context.Context, options *MessageOptions) error {
 	}
 
 	if err != nil {
-		s.emit(EventSessionError, map[string]interface{}{"error": err.Error()})
+		s.emitSessionError(err)
 	}
 
 	return err
@@ -181,8 +281,7 @@ func (s *Session) SendAndWait(ctx context.Context, options *MessageOptions) (*Se
 	errCh := make(chan error, 1)
 
 	handler := func(event SessionEvent) {
-		switch event.EventType {
-		case EventAssistantMessage, EventSessionIdle, EventSessionError:
+		if event.EventType == EventAssistantMessage || event.EventType == EventSessionIdle || isSessionErrorEvent(event.EventType) {
 			select {
 			case resultCh <- &event:
 			default:
@@ -206,6 +305,76 @@ func (s *Session) SendAndWait(ctx context.Context, options *MessageOptions) (*Se
 	}
 }
 
+// SendStructured sends a message and unmarshals the assistant's final
+// response into out, which should be a pointer. It's meant for use with a
+// session whose GenerationConfig sets ResponseMimeType to
+// "application/json" (and typically ResponseSchema, e.g. via
+// ResponseSchemaFromGo); if ResponseSchema was set, the response is
+// validated against it first, returning a *SchemaValidationError naming
+// the offending JSON path rather than a confusing unmarshal error.
+func (s *Session) SendStructured(ctx context.Context, options *MessageOptions, out any) error {
+	event, err := s.SendAndWait(ctx, options)
+	if err != nil {
+		return err
+	}
+	if isSessionErrorEvent(event.EventType) {
+		if msg, ok := event.Data["error"].(string); ok {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("session error: %s", event.EventType)
+	}
+
+	content, _ := event.Data["content"].(string)
+
+	chunk := LLMChunk{Content: content}
+	if s.generationConfig != nil {
+		chunk.responseSchema = s.generationConfig.ResponseSchema
+	}
+
+	return chunk.UnmarshalStructured(out)
+}
+
+// EstimateCost approximates the prompt token count and USD cost of sending
+// opts through s, without making a request — useful for budgeting before
+// invocation. It tokenizes the prompt, context, and any attachment
+// payloads with a lightweight ~4-bytes-per-token approximation (see
+// approxTokenCount; expect roughly ±10% accuracy, not an exact tokenizer
+// count) and prices the result against s's model via GetGeminiCLIModels/
+// GetGeminiCLIEmbeddingModels. CompletionTokens is always 0, since the
+// response hasn't happened yet. Returns a *ConfigurationError if s's model
+// isn't in either catalog.
+func (s *Session) EstimateCost(opts MessageOptions) (LLMUsage, float64, error) {
+	info, ok := GetGeminiCLIModels()[s.model]
+	if !ok {
+		info, ok = GetGeminiCLIEmbeddingModels()[s.model]
+		if !ok {
+			return LLMUsage{}, 0, NewConfigurationError(fmt.Sprintf("unknown model %q, cannot estimate cost", s.model))
+		}
+	}
+
+	promptTokens := approxTokenCount(opts.Prompt) + approxTokenCount(opts.Context)
+	for _, att := range opts.Attachments {
+		promptTokens += approxTokenCount(att.Data)
+	}
+
+	usage := LLMUsage{PromptTokens: promptTokens, TotalTokens: promptTokens}
+	costUSD := float64(promptTokens) * info.InputPrice
+	return usage, costUSD, nil
+}
+
+// Embed generates embeddings for inputs through the session's backend. It
+// does not touch the session's message history or model field — embedding
+// is a separate, stateless call that callers typically make against a
+// dedicated embedding model, so model is passed explicitly. It returns a
+// ConfigurationError if the backend doesn't implement EmbeddingProvider.
+func (s *Session) Embed(ctx context.Context, model string, inputs []EmbedInput, opts *EmbedOptions) (*EmbedResponse, error) {
+	embedder, ok := s.backend.(EmbeddingProvider)
+	if !ok {
+		return nil, NewConfigurationError("backend does not support embeddings")
+	}
+	return embedder.Embed(ctx, model, inputs, opts)
+}
+
 func (s *Session) streamResponse(ctx context.Context) error {
 	var fullContent string
 	var fullReasoning string
@@ -216,6 +385,7 @@ func (s *Session) streamResponse(ctx context.Context) error {
 	messages := make([]Message, len(s.messages))
 	copy(messages, s.messages)
 	tools := s.tools
+	manual := s.manualToolDispatch
 	s.mu.RUnlock()
 
 	stream, err := s.backend.CompleteStreaming(
@@ -261,6 +431,15 @@ func (s *Session) streamResponse(ctx context.Context) error {
 		}
 	}
 
+	if len(allToolCalls) > 0 && manual {
+		return s.deferToolCalls(LLMChunk{
+			Content:          fullContent,
+			ReasoningContent: fullReasoning,
+			ToolCalls:        allToolCalls,
+			Usage:            finalUsage,
+		})
+	}
+
 	if len(allToolCalls) > 0 {
 		if err := s.handleToolCalls(ctx, allToolCalls); err != nil {
 			return err
@@ -276,6 +455,7 @@ func (s *Session) streamResponse(ctx context.Context) error {
 	s.mu.Lock()
 	s.messages = append(s.messages, assistantMessage)
 	s.mu.Unlock()
+	s.recordMessage(assistantMessage)
 
 	if fullReasoning != "" {
 		s.emit(EventAssistantReasoning, map[string]interface{}{
@@ -299,6 +479,7 @@ func (s *Session) getResponse(ctx context.Context) error {
 	messages := make([]Message, len(s.messages))
 	copy(messages, s.messages)
 	tools := s.tools
+	manual := s.manualToolDispatch
 	s.mu.RUnlock()
 
 	chunk, err := s.backend.Complete(
@@ -313,6 +494,10 @@ func (s *Session) getResponse(ctx context.Context) error {
 		return err
 	}
 
+	if len(chunk.ToolCalls) > 0 && manual {
+		return s.deferToolCalls(*chunk)
+	}
+
 	if len(chunk.ToolCalls) > 0 {
 		if err := s.handleToolCalls(ctx, chunk.ToolCalls); err != nil {
 			return err
@@ -328,6 +513,7 @@ func (s *Session) getResponse(ctx context.Context) error {
 	s.mu.Lock()
 	s.messages = append(s.messages, assistantMessage)
 	s.mu.Unlock()
+	s.recordMessage(assistantMessage)
 
 	if chunk.ReasoningContent != "" {
 		s.emit(EventAssistantReasoning, map[string]interface{}{
@@ -358,18 +544,21 @@ func (s *Session) handleToolCalls(ctx context.Context, toolCalls []ToolCall) err
 
 		s.mu.RLock()
 		handler, ok := s.toolHandlers[toolName]
+		policy := s.approvalPolicy
 		s.mu.RUnlock()
 
 		if !ok {
 			fmt.Printf("Warning: No handler for tool: %s\n", toolName)
-			s.mu.Lock()
-			s.messages = append(s.messages, Message{
+			notFoundMessage := Message{
 				Role:       RoleUser,
 				Content:    fmt.Sprintf("Error: Tool '%s' not found", toolName),
 				Name:       toolName,
 				ToolCallID: tc.ID,
-			})
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, notFoundMessage)
 			s.mu.Unlock()
+			s.recordMessage(notFoundMessage)
 			continue
 		}
 
@@ -384,6 +573,44 @@ func (s *Session) handleToolCalls(ctx context.Context, toolCalls []ToolCall) err
 			CallID:    tc.ID,
 		}
 
+		if policy != nil {
+			s.emit(EventToolCallPending, map[string]interface{}{
+				"name":      toolName,
+				"arguments": json.RawMessage(tc.Function.Arguments),
+				"callId":    tc.ID,
+			})
+
+			decision := policy(ctx, invocation)
+			switch decision.action {
+			case approvalReject:
+				reason := decision.reason
+				if reason == "" {
+					reason = fmt.Sprintf("Tool call '%s' was rejected", toolName)
+				}
+				result := RejectedResult(reason)
+
+				s.emit(EventToolResult, map[string]interface{}{
+					"name":   toolName,
+					"callId": tc.ID,
+					"result": result.TextResultForLLM,
+				})
+
+				rejectedMessage := Message{
+					Role:       RoleUser,
+					Content:    result.TextResultForLLM,
+					Name:       toolName,
+					ToolCallID: tc.ID,
+				}
+				s.mu.Lock()
+				s.messages = append(s.messages, rejectedMessage)
+				s.mu.Unlock()
+				s.recordMessage(rejectedMessage)
+				continue
+			case approvalModify:
+				invocation.Arguments = decision.args
+			}
+		}
+
 		result := handler(ctx, invocation)
 		resultText := result.TextResultForLLM
 		if resultText == "" {
@@ -396,16 +623,188 @@ func (s *Session) handleToolCalls(ctx context.Context, toolCalls []ToolCall) err
 			"result": resultText,
 		})
 
-		s.mu.Lock()
-		s.messages = append(s.messages, Message{
+		resultMessage := Message{
 			Role:       RoleUser,
 			Content:    resultText,
 			Name:       toolName,
 			ToolCallID: tc.ID,
+		}
+		s.mu.Lock()
+		s.messages = append(s.messages, resultMessage)
+		s.mu.Unlock()
+		s.recordMessage(resultMessage)
+	}
+
+	return nil
+}
+
+// deferToolCalls appends the assistant's message to history and stashes its
+// tool calls for SubmitToolResults instead of invoking registered handlers.
+// Used for getResponse/streamResponse when ManualToolDispatch is enabled.
+func (s *Session) deferToolCalls(chunk LLMChunk) error {
+	assistantMessage := Message{
+		Role:      RoleAssistant,
+		Content:   chunk.Content,
+		ToolCalls: chunk.ToolCalls,
+	}
+
+	s.mu.Lock()
+	s.messages = append(s.messages, assistantMessage)
+	s.pendingToolCalls = chunk.ToolCalls
+	s.modifiedTime = time.Now()
+	s.mu.Unlock()
+	s.recordMessage(assistantMessage)
+
+	if chunk.ReasoningContent != "" {
+		s.emit(EventAssistantReasoning, map[string]interface{}{
+			"content": chunk.ReasoningContent,
 		})
+	}
+
+	s.emit(EventAssistantMessage, map[string]interface{}{
+		"content":   chunk.Content,
+		"toolCalls": chunk.ToolCalls,
+		"usage":     chunk.Usage,
+	})
+
+	s.emit(EventToolCallsPending, map[string]interface{}{
+		"toolCalls": chunk.ToolCalls,
+	})
+
+	return nil
+}
+
+// SubmitToolResults supplies results for the tool calls most recently
+// returned while ManualToolDispatch is enabled, appends them to the
+// transcript in order, and requests the model's next turn. Results must be
+// in the same order as the ToolCalls from PendingToolCalls.
+func (s *Session) SubmitToolResults(ctx context.Context, results []ToolResult) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return NewSessionClosedError(s.sessionID)
+	}
+
+	pending := s.pendingToolCalls
+	if len(pending) == 0 {
+		s.mu.Unlock()
+		return NewConfigurationError("no pending tool calls to submit results for")
+	}
+	if len(results) != len(pending) {
 		s.mu.Unlock()
+		return NewConfigurationError(fmt.Sprintf("expected %d tool result(s), got %d", len(pending), len(results)))
+	}
+
+	resultMessages := make([]Message, len(pending))
+	for i, tc := range pending {
+		resultText := results[i].TextResultForLLM
+		if resultText == "" {
+			resultText = "Success"
+		}
+		resultMessages[i] = Message{
+			Role:       RoleUser,
+			Content:    resultText,
+			Name:       tc.Function.Name,
+			ToolCallID: tc.ID,
+		}
+		s.messages = append(s.messages, resultMessages[i])
+	}
+
+	s.pendingToolCalls = nil
+	s.modifiedTime = time.Now()
+	s.mu.Unlock()
+
+	for i, tc := range pending {
+		s.emit(EventToolResult, map[string]interface{}{
+			"name":   tc.Function.Name,
+			"callId": tc.ID,
+			"result": results[i].TextResultForLLM,
+		})
+		s.recordMessage(resultMessages[i])
+	}
+
+	var err error
+	if s.streaming {
+		err = s.streamResponse(ctx)
+	} else {
+		err = s.getResponse(ctx)
 	}
 
+	if err != nil {
+		s.emitSessionError(err)
+	}
+
+	return err
+}
+
+// Fork creates a new Session that deep-copies s's current history into a
+// fresh session ID. Combine with RewindTo to implement "edit and re-prompt":
+// fork, rewind the copy to an earlier message, then Send a replacement.
+// The new session's ParentSessionID and ForkPoint record where it branched
+// from, and s emits EventSessionForked so listeners can track the tree.
+func (s *Session) Fork() *Session {
+	s.mu.RLock()
+	messages := make([]Message, len(s.messages))
+	copy(messages, s.messages)
+	tools := append([]Tool(nil), s.tools...)
+	toolHandlers := make(map[string]ToolHandler, len(s.toolHandlers))
+	for name, handler := range s.toolHandlers {
+		toolHandlers[name] = handler
+	}
+	parentID := s.sessionID
+	forkPoint := len(messages)
+	model := s.model
+	backend := s.backend
+	systemMessage := s.systemMessage
+	generationConfig := s.generationConfig
+	thinkingConfig := s.thinkingConfig
+	streaming := s.streaming
+	approvalPolicy := s.approvalPolicy
+	manualToolDispatch := s.manualToolDispatch
+	s.mu.RUnlock()
+
+	now := time.Now()
+	forked := &Session{
+		sessionID:          GenerateSessionID(),
+		model:              model,
+		backend:            backend,
+		tools:              tools,
+		toolHandlers:       toolHandlers,
+		systemMessage:      systemMessage,
+		generationConfig:   generationConfig,
+		thinkingConfig:     thinkingConfig,
+		streaming:          streaming,
+		approvalPolicy:     approvalPolicy,
+		manualToolDispatch: manualToolDispatch,
+		messages:           messages,
+		eventHandlers:      make([]EventHandler, 0),
+		startTime:          now,
+		modifiedTime:       now,
+		parentSessionID:    parentID,
+		forkPoint:          forkPoint,
+	}
+
+	s.emit(EventSessionForked, map[string]interface{}{
+		"childSessionId": forked.sessionID,
+		"forkPoint":      forkPoint,
+	})
+
+	return forked
+}
+
+// RewindTo truncates history to the first messageIndex messages, discarding
+// everything after. Typically used on a forked session before editing and
+// re-sending a prior message.
+func (s *Session) RewindTo(messageIndex int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if messageIndex < 0 || messageIndex > len(s.messages) {
+		return NewConfigurationError(fmt.Sprintf("message index %d out of range [0, %d]", messageIndex, len(s.messages)))
+	}
+
+	s.messages = s.messages[:messageIndex]
+	s.modifiedTime = time.Now()
 	return nil
 }
 
@@ -434,7 +833,6 @@ func (s *Session) RemoveTool(toolName string) {
 // ClearHistory clears the conversation history
 func (s *Session) ClearHistory() {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	s.messages = make([]Message, 0)
 	if s.systemMessage != "" {
@@ -444,6 +842,15 @@ func (s *Session) ClearHistory() {
 		})
 	}
 	s.modifiedTime = time.Now()
+	store := s.store
+
+	s.mu.Unlock()
+
+	if store != nil {
+		if err := store.Save(context.Background(), s.Snapshot()); err != nil {
+			fmt.Printf("Warning: Failed to persist cleared history: %v\n", err)
+		}
+	}
 }
 
 // Destroy closes and cleans up the session
@@ -497,3 +904,58 @@ func RejectedResult(text string) ToolResult {
 func GenerateSessionID() string {
 	return uuid.New().String()
 }
+
+// Snapshot captures the session's current state as a SessionSnapshot,
+// suitable for persisting via a SessionStore and later restoring with
+// RestoreSession.
+func (s *Session) Snapshot() *SessionSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]Message, len(s.messages))
+	copy(messages, s.messages)
+
+	tools := make([]Tool, len(s.tools))
+	copy(tools, s.tools)
+
+	return &SessionSnapshot{
+		SessionID:        s.sessionID,
+		Model:            s.model,
+		SystemMessage:    s.systemMessage,
+		Tools:            tools,
+		GenerationConfig: s.generationConfig,
+		ThinkingConfig:   s.thinkingConfig,
+		Streaming:        s.streaming,
+		Messages:         messages,
+		StartTime:        s.startTime,
+		ModifiedTime:     s.modifiedTime,
+		ParentSessionID:  s.parentSessionID,
+		ForkPoint:        s.forkPoint,
+	}
+}
+
+// RestoreSession reconstructs a Session from a snapshot previously produced
+// by Session.Snapshot, reattaching it to backend for future turns.
+func RestoreSession(snapshot *SessionSnapshot, backend ChatCompletionProvider) *Session {
+	s := NewSession(
+		snapshot.SessionID,
+		snapshot.Model,
+		backend,
+		snapshot.Tools,
+		"",
+		snapshot.GenerationConfig,
+		snapshot.ThinkingConfig,
+		snapshot.Streaming,
+	)
+
+	s.mu.Lock()
+	s.systemMessage = snapshot.SystemMessage
+	s.messages = append([]Message(nil), snapshot.Messages...)
+	s.startTime = snapshot.StartTime
+	s.modifiedTime = snapshot.ModifiedTime
+	s.parentSessionID = snapshot.ParentSessionID
+	s.forkPoint = snapshot.ForkPoint
+	s.mu.Unlock()
+
+	return s
+}
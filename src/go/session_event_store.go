@@ -0,0 +1,464 @@
+package geminisdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExportFormat selects the transcript shape produced by
+// EventLogStore.Export.
+type ExportFormat string
+
+const (
+	ExportJSONL      ExportFormat = "jsonl"
+	ExportMarkdown   ExportFormat = "markdown"
+	ExportOpenAIChat ExportFormat = "openai-chat"
+)
+
+// LoggedEvent is one line of an EventLogStore's append-only log: a
+// SessionEvent plus the wall-clock time it was recorded.
+type LoggedEvent struct {
+	SessionEvent
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EventLogStore persists every SessionEvent a Session emits as an
+// append-only log, complementing SessionStore's message-snapshot
+// persistence with full event-level replay. Resume rehydrates a live
+// Session from the log, Fork branches a new log at an earlier event for
+// A/B experiments, and Export hands the transcript to other tools.
+type EventLogStore interface {
+	// Attach registers session with the store so every event it emits from
+	// here on is appended to its log, and returns session for chaining.
+	Attach(session *Session) *Session
+	Events(ctx context.Context, sessionID string) ([]LoggedEvent, error)
+	Resume(ctx context.Context, sessionID string, backend ChatCompletionProvider) (*Session, error)
+	Fork(ctx context.Context, sessionID string, atEventIndex int, backend ChatCompletionProvider) (*Session, error)
+	Export(ctx context.Context, sessionID string, format ExportFormat) ([]byte, error)
+}
+
+// FileEventLogStore writes one append-only JSONL file per session,
+// "<dir>/<session_id>.jsonl", plus a companion "<dir>/<session_id>.metadata.json"
+// matching SessionMetadata that's kept current on every event.
+type FileEventLogStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileEventLogStore creates a store rooted at dir (empty resolves to
+// ~/.gemini/sessions), creating it on first write.
+func NewFileEventLogStore(dir string) *FileEventLogStore {
+	if dir == "" {
+		homeDir, _ := os.UserHomeDir()
+		dir = filepath.Join(homeDir, GeminiDir, "sessions")
+	}
+	return &FileEventLogStore{dir: dir}
+}
+
+func (s *FileEventLogStore) logPath(sessionID string) string {
+	return filepath.Join(s.dir, sessionID+".jsonl")
+}
+
+func (s *FileEventLogStore) metadataPath(sessionID string) string {
+	return filepath.Join(s.dir, sessionID+".metadata.json")
+}
+
+// Attach registers session with the store so every event it emits from here
+// on is appended to its log. Resume and Fork call this on the session they
+// return, so a resumed or forked conversation keeps logging automatically.
+func (s *FileEventLogStore) Attach(session *Session) *Session {
+	sessionID := session.SessionID()
+
+	s.mu.Lock()
+	err := s.ensureMetadata(sessionID, session.Model())
+	s.mu.Unlock()
+	if err != nil {
+		fmt.Printf("Warning: Failed to initialize session metadata: %v\n", err)
+	}
+
+	session.On(func(event SessionEvent) {
+		if err := s.append(sessionID, event); err != nil {
+			fmt.Printf("Warning: Failed to persist session event: %v\n", err)
+		}
+	})
+
+	return session
+}
+
+func (s *FileEventLogStore) append(sessionID string, event SessionEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(LoggedEvent{SessionEvent: event, Timestamp: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.logPath(sessionID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	return s.ensureMetadata(sessionID, "")
+}
+
+// ensureMetadata creates sessionID's metadata.json on first call and bumps
+// its ModifiedTime on every subsequent one; model is only written when
+// non-empty, so callers recording an event (which doesn't know the model)
+// can pass "" without clobbering what Attach already set. Callers must hold
+// s.mu.
+func (s *FileEventLogStore) ensureMetadata(sessionID, model string) error {
+	meta, err := s.readMetadataLocked(sessionID)
+	now := time.Now().Format(time.RFC3339)
+	if err != nil {
+		meta = SessionMetadata{SessionID: sessionID, StartTime: now}
+	}
+	meta.ModifiedTime = now
+	if model != "" {
+		meta.Model = model
+	}
+	return s.writeMetadataLocked(meta)
+}
+
+func (s *FileEventLogStore) readMetadataLocked(sessionID string) (SessionMetadata, error) {
+	data, err := os.ReadFile(s.metadataPath(sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return SessionMetadata{}, NewSessionNotFoundError(sessionID)
+		}
+		return SessionMetadata{}, err
+	}
+
+	var meta SessionMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return SessionMetadata{}, err
+	}
+	return meta, nil
+}
+
+func (s *FileEventLogStore) writeMetadataLocked(meta SessionMetadata) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.metadataPath(meta.SessionID), data, 0o600)
+}
+
+// Events reads back sessionID's full logged event history in append order.
+func (s *FileEventLogStore) Events(ctx context.Context, sessionID string) ([]LoggedEvent, error) {
+	f, err := os.Open(s.logPath(sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewSessionNotFoundError(sessionID)
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []LoggedEvent
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var le LoggedEvent
+		if err := dec.Decode(&le); err != nil {
+			return nil, err
+		}
+		events = append(events, le)
+	}
+	return events, nil
+}
+
+// Resume rehydrates a live Session from sessionID's logged events — message
+// history and pending tool-call state — reattaches it to backend for future
+// turns, and emits EventSessionResumed so subscribers can tell it apart from
+// a freshly created session. Unlike RestoreSession, it cannot recover the
+// original system message, tools, generation or thinking config, or
+// streaming setting: none of them is logged as an event or kept in
+// SessionMetadata, so the resumed session starts without them.
+func (s *FileEventLogStore) Resume(ctx context.Context, sessionID string, backend ChatCompletionProvider) (*Session, error) {
+	s.mu.Lock()
+	meta, err := s.readMetadataLocked(sessionID)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.Events(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := replayEvents(meta, events, backend)
+	s.Attach(session)
+	session.emit(EventSessionResumed, map[string]interface{}{
+		"eventCount": len(events),
+	})
+
+	return session, nil
+}
+
+// Fork branches a new session off sessionID's log at atEventIndex — the
+// number of leading events to keep, mirroring Session.RewindTo's
+// messageIndex semantics — so the original log is untouched and the copy
+// can diverge independently. It's meant for A/B experiments: resume twice
+// at the same point, send each a different next message, and compare.
+func (s *FileEventLogStore) Fork(ctx context.Context, sessionID string, atEventIndex int, backend ChatCompletionProvider) (*Session, error) {
+	s.mu.Lock()
+	meta, err := s.readMetadataLocked(sessionID)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.Events(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if atEventIndex < 0 || atEventIndex > len(events) {
+		return nil, NewConfigurationError(fmt.Sprintf("event index %d out of range [0, %d]", atEventIndex, len(events)))
+	}
+	kept := events[:atEventIndex]
+
+	forked := replayEvents(meta, kept, backend)
+
+	now := time.Now()
+	forked.mu.Lock()
+	newID := GenerateSessionID()
+	forked.sessionID = newID
+	forked.parentSessionID = sessionID
+	forked.forkPoint = len(forked.messages)
+	forked.startTime = now
+	forked.modifiedTime = now
+	forked.mu.Unlock()
+
+	s.mu.Lock()
+	err = s.writeLogLocked(newID, kept)
+	if err == nil {
+		err = s.writeMetadataLocked(SessionMetadata{
+			SessionID:    newID,
+			StartTime:    now.Format(time.RFC3339),
+			ModifiedTime: now.Format(time.RFC3339),
+			Summary:      meta.Summary,
+			Model:        meta.Model,
+		})
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.Attach(forked)
+	return forked, nil
+}
+
+// writeLogLocked overwrites sessionID's log with events, preserving their
+// original timestamps. Callers must hold s.mu.
+func (s *FileEventLogStore) writeLogLocked(sessionID string, events []LoggedEvent) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.Create(s.logPath(sessionID))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, le := range events {
+		if err := enc.Encode(le); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayEvents reconstructs a Session's message history and pending
+// tool-call state by walking its logged events in order. It only relies on
+// the events a FileEventLogStore-attached Session actually emits
+// (EventUserMessage, EventAssistantMessage, EventToolResult,
+// EventToolCallsPending); anything else (deltas, reasoning, tool-call
+// announcements) is replay-irrelevant and skipped.
+func replayEvents(meta SessionMetadata, events []LoggedEvent, backend ChatCompletionProvider) *Session {
+	session := NewSession(meta.SessionID, meta.Model, backend, nil, "", nil, nil, false)
+
+	var messages []Message
+	var pending []ToolCall
+	resolved := make(map[string]bool)
+	for _, le := range events {
+		switch le.EventType {
+		case EventUserMessage:
+			content, _ := le.Data["content"].(string)
+			messages = append(messages, Message{Role: RoleUser, Content: content})
+			pending = nil
+			resolved = make(map[string]bool)
+		case EventAssistantMessage:
+			content, _ := le.Data["content"].(string)
+			messages = append(messages, Message{
+				Role:      RoleAssistant,
+				Content:   content,
+				ToolCalls: decodeToolCalls(le.Data["toolCalls"]),
+			})
+		case EventToolResult:
+			name, _ := le.Data["name"].(string)
+			callID, _ := le.Data["callId"].(string)
+			result, _ := le.Data["result"].(string)
+			messages = append(messages, Message{Role: RoleUser, Content: result, Name: name, ToolCallID: callID})
+			resolved[callID] = true
+		case EventToolCallsPending:
+			pending = decodeToolCalls(le.Data["toolCalls"])
+		}
+	}
+
+	pending = unresolvedToolCalls(pending, resolved)
+
+	startTime, _ := time.Parse(time.RFC3339, meta.StartTime)
+	modifiedTime, _ := time.Parse(time.RFC3339, meta.ModifiedTime)
+
+	session.mu.Lock()
+	session.messages = messages
+	session.pendingToolCalls = pending
+	if !startTime.IsZero() {
+		session.startTime = startTime
+	}
+	if !modifiedTime.IsZero() {
+		session.modifiedTime = modifiedTime
+	}
+	session.mu.Unlock()
+
+	return session
+}
+
+// decodeToolCalls round-trips v (a SessionEvent.Data["toolCalls"] value,
+// already decoded into generic map[string]interface{}s by encoding/json)
+// back into []ToolCall.
+func decodeToolCalls(v interface{}) []ToolCall {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var calls []ToolCall
+	if err := json.Unmarshal(raw, &calls); err != nil {
+		return nil
+	}
+	return calls
+}
+
+// unresolvedToolCalls filters calls down to those whose ID has not already
+// been resolved by a replayed EventToolResult.
+func unresolvedToolCalls(calls []ToolCall, resolved map[string]bool) []ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	var out []ToolCall
+	for _, c := range calls {
+		if !resolved[c.ID] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Export renders sessionID's event log as format: "jsonl" returns the raw
+// log verbatim, "markdown" produces a human-readable transcript, and
+// "openai-chat" produces a {"messages": [...]} document in OpenAI's chat
+// completion message shape, ready to hand to another tool.
+func (s *FileEventLogStore) Export(ctx context.Context, sessionID string, format ExportFormat) ([]byte, error) {
+	switch format {
+	case ExportJSONL, "":
+		data, err := os.ReadFile(s.logPath(sessionID))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, NewSessionNotFoundError(sessionID)
+			}
+			return nil, err
+		}
+		return data, nil
+	case ExportMarkdown:
+		events, err := s.Events(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		return exportMarkdown(sessionID, events), nil
+	case ExportOpenAIChat:
+		events, err := s.Events(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		return exportOpenAIChat(events)
+	default:
+		return nil, NewConfigurationError(fmt.Sprintf("unsupported export format %q", format))
+	}
+}
+
+func exportMarkdown(sessionID string, events []LoggedEvent) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Session %s\n\n", sessionID)
+
+	for _, le := range events {
+		switch le.EventType {
+		case EventUserMessage:
+			content, _ := le.Data["content"].(string)
+			fmt.Fprintf(&b, "**User:**\n\n%s\n\n", content)
+		case EventAssistantMessage:
+			content, _ := le.Data["content"].(string)
+			fmt.Fprintf(&b, "**Assistant:**\n\n%s\n\n", content)
+		case EventToolResult:
+			name, _ := le.Data["name"].(string)
+			result, _ := le.Data["result"].(string)
+			fmt.Fprintf(&b, "**Tool (%s):**\n\n%s\n\n", name, result)
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// openAIChatMessage is one entry of exportOpenAIChat's "messages" array,
+// shaped like an OpenAI chat completion request message.
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	Name    string `json:"name,omitempty"`
+}
+
+func exportOpenAIChat(events []LoggedEvent) ([]byte, error) {
+	var messages []openAIChatMessage
+	for _, le := range events {
+		switch le.EventType {
+		case EventUserMessage:
+			content, _ := le.Data["content"].(string)
+			messages = append(messages, openAIChatMessage{Role: "user", Content: content})
+		case EventAssistantMessage:
+			content, _ := le.Data["content"].(string)
+			messages = append(messages, openAIChatMessage{Role: "assistant", Content: content})
+		case EventToolResult:
+			name, _ := le.Data["name"].(string)
+			result, _ := le.Data["result"].(string)
+			messages = append(messages, openAIChatMessage{Role: "tool", Content: result, Name: name})
+		}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{"messages": messages}, "", "  ")
+}
@@ -0,0 +1,290 @@
+package geminisdk
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteSessionStore persists sessions, their messages, and each message's
+// tool calls in a SQLite database, with messages foreign-keyed to their
+// owning session so a Delete cascades. Use it in place of FileSessionStore
+// when many sessions need queryable, crash-safe storage.
+type SQLiteSessionStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteSessionStore opens (creating if necessary) a SQLite database at
+// path and ensures its schema exists.
+func NewSQLiteSessionStore(path string) (*SQLiteSessionStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// foreign_keys is a per-connection setting, so keep a single connection
+	// to make the PRAGMA below (and with it the cascade) apply to every query.
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	store := &SQLiteSessionStore{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (s *SQLiteSessionStore) migrate() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS sessions (
+			session_id        TEXT PRIMARY KEY,
+			model             TEXT NOT NULL,
+			system_message    TEXT,
+			tools             TEXT,
+			generation_config TEXT,
+			thinking_config   TEXT,
+			streaming         INTEGER NOT NULL DEFAULT 0,
+			parent_session_id TEXT,
+			fork_point        INTEGER NOT NULL DEFAULT 0,
+			start_time        TEXT NOT NULL,
+			modified_time     TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS messages (
+			session_id   TEXT NOT NULL REFERENCES sessions(session_id) ON DELETE CASCADE,
+			seq          INTEGER NOT NULL,
+			role         TEXT NOT NULL,
+			content      TEXT,
+			name         TEXT,
+			tool_call_id TEXT,
+			tool_calls   TEXT,
+			PRIMARY KEY (session_id, seq)
+		);
+	`)
+	return err
+}
+
+// Close closes the underlying database handle.
+func (s *SQLiteSessionStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteSessionStore) Save(ctx context.Context, snapshot *SessionSnapshot) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tools, err := json.Marshal(snapshot.Tools)
+	if err != nil {
+		return err
+	}
+	generationConfig, err := json.Marshal(snapshot.GenerationConfig)
+	if err != nil {
+		return err
+	}
+	thinkingConfig, err := json.Marshal(snapshot.ThinkingConfig)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO sessions (session_id, model, system_message, tools, generation_config, thinking_config, streaming, parent_session_id, fork_point, start_time, modified_time)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			model = excluded.model,
+			system_message = excluded.system_message,
+			tools = excluded.tools,
+			generation_config = excluded.generation_config,
+			thinking_config = excluded.thinking_config,
+			streaming = excluded.streaming,
+			parent_session_id = excluded.parent_session_id,
+			fork_point = excluded.fork_point,
+			modified_time = excluded.modified_time
+	`,
+		snapshot.SessionID, snapshot.Model, snapshot.SystemMessage, string(tools), string(generationConfig), string(thinkingConfig),
+		snapshot.Streaming, snapshot.ParentSessionID, snapshot.ForkPoint,
+		snapshot.StartTime.Format(time.RFC3339Nano), snapshot.ModifiedTime.Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM messages WHERE session_id = ?`, snapshot.SessionID); err != nil {
+		return err
+	}
+
+	for i, msg := range snapshot.Messages {
+		if err := insertMessage(ctx, tx, snapshot.SessionID, i, msg); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertMessage(ctx context.Context, tx *sql.Tx, sessionID string, seq int, msg Message) error {
+	toolCalls, err := json.Marshal(msg.ToolCalls)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO messages (session_id, seq, role, content, name, tool_call_id, tool_calls)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, sessionID, seq, string(msg.Role), msg.Content, msg.Name, msg.ToolCallID, string(toolCalls))
+	return err
+}
+
+func (s *SQLiteSessionStore) Load(ctx context.Context, sessionID string) (*SessionSnapshot, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT model, system_message, tools, generation_config, thinking_config, streaming, parent_session_id, fork_point, start_time, modified_time
+		FROM sessions WHERE session_id = ?
+	`, sessionID)
+
+	var (
+		model, systemMessage, tools, generationConfig, thinkingConfig, parentSessionID string
+		streaming                                                                     bool
+		forkPoint                                                                     int
+		startTime, modifiedTime                                                       string
+	)
+
+	if err := row.Scan(&model, &systemMessage, &tools, &generationConfig, &thinkingConfig, &streaming, &parentSessionID, &forkPoint, &startTime, &modifiedTime); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, NewSessionNotFoundError(sessionID)
+		}
+		return nil, err
+	}
+
+	snapshot := &SessionSnapshot{
+		SessionID:       sessionID,
+		Model:           model,
+		SystemMessage:   systemMessage,
+		Streaming:       streaming,
+		ParentSessionID: parentSessionID,
+		ForkPoint:       forkPoint,
+	}
+
+	if err := json.Unmarshal([]byte(tools), &snapshot.Tools); err != nil {
+		return nil, err
+	}
+	if generationConfig != "null" && generationConfig != "" {
+		if err := json.Unmarshal([]byte(generationConfig), &snapshot.GenerationConfig); err != nil {
+			return nil, err
+		}
+	}
+	if thinkingConfig != "null" && thinkingConfig != "" {
+		if err := json.Unmarshal([]byte(thinkingConfig), &snapshot.ThinkingConfig); err != nil {
+			return nil, err
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, startTime); err == nil {
+		snapshot.StartTime = t
+	}
+	if t, err := time.Parse(time.RFC3339Nano, modifiedTime); err == nil {
+		snapshot.ModifiedTime = t
+	}
+
+	messages, err := s.loadMessages(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Messages = messages
+
+	return snapshot, nil
+}
+
+func (s *SQLiteSessionStore) loadMessages(ctx context.Context, sessionID string) ([]Message, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT role, content, name, tool_call_id, tool_calls
+		FROM messages WHERE session_id = ? ORDER BY seq ASC
+	`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var role, content, name, toolCallID, toolCalls string
+		if err := rows.Scan(&role, &content, &name, &toolCallID, &toolCalls); err != nil {
+			return nil, err
+		}
+
+		msg := Message{
+			Role:       Role(role),
+			Content:    content,
+			Name:       name,
+			ToolCallID: toolCallID,
+		}
+		if toolCalls != "" && toolCalls != "null" {
+			if err := json.Unmarshal([]byte(toolCalls), &msg.ToolCalls); err != nil {
+				return nil, err
+			}
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+func (s *SQLiteSessionStore) List(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT session_id FROM sessions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (s *SQLiteSessionStore) Delete(ctx context.Context, sessionID string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE session_id = ?`, sessionID)
+	return err
+}
+
+func (s *SQLiteSessionStore) AppendMessage(ctx context.Context, sessionID string, message Message) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO sessions (session_id, model, system_message, tools, generation_config, thinking_config, parent_session_id, start_time, modified_time)
+		VALUES (?, '', '', '[]', 'null', 'null', '', ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET modified_time = excluded.modified_time
+	`, sessionID, time.Now().Format(time.RFC3339Nano), time.Now().Format(time.RFC3339Nano)); err != nil {
+		return err
+	}
+
+	var nextSeq int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(seq) + 1, 0) FROM messages WHERE session_id = ?`, sessionID).Scan(&nextSeq); err != nil {
+		return err
+	}
+
+	if err := insertMessage(ctx, tx, sessionID, nextSeq, message); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
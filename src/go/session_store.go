@@ -0,0 +1,213 @@
+package geminisdk
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionSnapshot is the serializable state of a Session, produced by
+// Session.Snapshot and consumed by RestoreSession. It captures everything
+// needed to resume a conversation: identity, configuration, and history.
+type SessionSnapshot struct {
+	SessionID        string            `json:"session_id"`
+	Model            string            `json:"model"`
+	SystemMessage    string            `json:"system_message"`
+	Tools            []Tool            `json:"tools,omitempty"`
+	GenerationConfig *GenerationConfig `json:"generation_config,omitempty"`
+	ThinkingConfig   *ThinkingConfig   `json:"thinking_config,omitempty"`
+	Streaming        bool              `json:"streaming"`
+	Messages         []Message         `json:"messages"`
+	StartTime        time.Time         `json:"start_time"`
+	ModifiedTime     time.Time         `json:"modified_time"`
+	ParentSessionID  string            `json:"parent_session_id,omitempty"`
+	ForkPoint        int               `json:"fork_point,omitempty"`
+}
+
+// SessionStore persists Session snapshots so conversations can survive
+// process restarts. AppendMessage lets callers persist history
+// incrementally, one message at a time, instead of rewriting the whole
+// snapshot after every turn; implementations that can only store whole
+// snapshots (FileSessionStore, MemorySessionStore) fall back to a
+// load-append-save cycle.
+type SessionStore interface {
+	Save(ctx context.Context, snapshot *SessionSnapshot) error
+	Load(ctx context.Context, sessionID string) (*SessionSnapshot, error)
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, sessionID string) error
+	AppendMessage(ctx context.Context, sessionID string, message Message) error
+}
+
+// FileSessionStore persists one JSON file per session under dir.
+type FileSessionStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileSessionStore creates a store that writes session snapshots as
+// "<dir>/<session_id>.json", creating dir on first write.
+func NewFileSessionStore(dir string) *FileSessionStore {
+	return &FileSessionStore{dir: dir}
+}
+
+func (s *FileSessionStore) path(sessionID string) string {
+	return filepath.Join(s.dir, sessionID+".json")
+}
+
+func (s *FileSessionStore) Save(ctx context.Context, snapshot *SessionSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path(snapshot.SessionID), data, 0600)
+}
+
+func (s *FileSessionStore) Load(ctx context.Context, sessionID string) (*SessionSnapshot, error) {
+	data, err := os.ReadFile(s.path(sessionID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, NewSessionNotFoundError(sessionID)
+		}
+		return nil, err
+	}
+
+	var snapshot SessionSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+func (s *FileSessionStore) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+
+	return ids, nil
+}
+
+func (s *FileSessionStore) AppendMessage(ctx context.Context, sessionID string, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, err := s.Load(ctx, sessionID)
+	if err != nil {
+		if _, ok := err.(*SessionNotFoundError); !ok {
+			return err
+		}
+		snapshot = &SessionSnapshot{SessionID: sessionID}
+	}
+
+	snapshot.Messages = append(snapshot.Messages, message)
+	snapshot.ModifiedTime = time.Now()
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path(sessionID), data, 0600)
+}
+
+func (s *FileSessionStore) Delete(ctx context.Context, sessionID string) error {
+	if err := os.Remove(s.path(sessionID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// MemorySessionStore keeps session snapshots in process memory.
+type MemorySessionStore struct {
+	mu        sync.RWMutex
+	snapshots map[string]*SessionSnapshot
+}
+
+// NewMemorySessionStore creates an empty in-memory session store.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{snapshots: make(map[string]*SessionSnapshot)}
+}
+
+func (s *MemorySessionStore) Save(ctx context.Context, snapshot *SessionSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *snapshot
+	s.snapshots[snapshot.SessionID] = &stored
+	return nil
+}
+
+func (s *MemorySessionStore) Load(ctx context.Context, sessionID string) (*SessionSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, ok := s.snapshots[sessionID]
+	if !ok {
+		return nil, NewSessionNotFoundError(sessionID)
+	}
+
+	stored := *snapshot
+	return &stored, nil
+}
+
+func (s *MemorySessionStore) List(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.snapshots))
+	for id := range s.snapshots {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *MemorySessionStore) AppendMessage(ctx context.Context, sessionID string, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[sessionID]
+	if !ok {
+		snapshot = &SessionSnapshot{SessionID: sessionID}
+		s.snapshots[sessionID] = snapshot
+	}
+
+	snapshot.Messages = append(snapshot.Messages, message)
+	snapshot.ModifiedTime = time.Now()
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(ctx context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.snapshots, sessionID)
+	return nil
+}
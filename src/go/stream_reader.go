@@ -0,0 +1,225 @@
+package geminisdk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// defaultStreamBufferSize is ChunkChannel's buffer capacity when the
+// caller doesn't configure one via StreamReaderOptions.
+const defaultStreamBufferSize = 100
+
+// DefaultMaxLineSize bounds a single SSE `data:` line. bufio.Scanner's
+// fixed 64KB line limit silently truncates a long tool-call response;
+// this is generous enough for that while still bounding memory per line.
+// Exported so other ChatCompletionProvider implementations (e.g.
+// providers/google) can reuse it alongside ReadLineWithLimit.
+const DefaultMaxLineSize = 1 << 20 // 1MB
+
+// BackpressureStrategy controls what a StreamReader does once its
+// internal buffer of undelivered chunks is full.
+type BackpressureStrategy int
+
+const (
+	// BackpressureBlock blocks the stream-reading goroutine until the
+	// consumer drains the buffer. The default; no chunk is ever dropped.
+	BackpressureBlock BackpressureStrategy = iota
+	// BackpressureDropOldest discards the oldest buffered chunk to make
+	// room for the newest one, trading completeness for freshness (e.g.
+	// a UI that only ever renders the latest partial response).
+	BackpressureDropOldest
+)
+
+// StreamReaderOptions configures a StreamReader returned by
+// Backend.CompleteStreamingReader.
+type StreamReaderOptions struct {
+	// BufferSize is ChunkChannel's capacity. Defaults to 100.
+	BufferSize int
+	// Backpressure selects what happens once BufferSize chunks are
+	// buffered and unread. Defaults to BackpressureBlock.
+	Backpressure BackpressureStrategy
+	// MaxLineSize bounds a single SSE line. Defaults to 1MB.
+	MaxLineSize int
+}
+
+func (o *StreamReaderOptions) withDefaults() *StreamReaderOptions {
+	out := StreamReaderOptions{}
+	if o != nil {
+		out = *o
+	}
+	if out.BufferSize <= 0 {
+		out.BufferSize = defaultStreamBufferSize
+	}
+	if out.MaxLineSize <= 0 {
+		out.MaxLineSize = DefaultMaxLineSize
+	}
+	return &out
+}
+
+// StreamReader owns a streaming completion's connection. Cancelling the
+// ctx passed to Backend.CompleteStreamingReader, or calling Close, closes
+// the underlying response body and unblocks a read parked mid-chunk.
+// SetReadDeadline arms an additional, independently movable deadline on
+// top of ctx.
+type StreamReader struct {
+	ch     chan StreamResult
+	dl     *deadline
+	cancel context.CancelFunc
+
+	closeOnce sync.Once
+}
+
+// Chunks returns the channel chunks (and the terminal error, if any) are
+// delivered on. It's closed once the stream ends, the same contract as
+// the ChunkChannel CompleteStreaming returns.
+func (s *StreamReader) Chunks() ChunkChannel {
+	return s.ch
+}
+
+// SetReadDeadline arms a deadline for reads off the underlying connection
+// that start after the call, independent of ctx; a read already in flight
+// keeps the deadline that was current when it started. A zero Time
+// disables it. It follows the cancel-channel pattern netstack's gonet
+// package uses for socket deadlines: an AfterFunc-armed channel that's
+// replaced — not reused — each time the deadline moves, so a read already
+// selecting on the old channel isn't woken early by a later call.
+func (s *StreamReader) SetReadDeadline(t time.Time) {
+	s.dl.set(t)
+}
+
+// Close tears down the stream: it cancels the context the reading
+// goroutine selects on, closing the underlying response body and, once
+// drained, ch. Safe to call more than once.
+func (s *StreamReader) Close() {
+	s.closeOnce.Do(s.cancel)
+}
+
+// deadline mirrors the cancel-channel pattern netstack's gonet package
+// uses for socket deadlines: a Read races a channel that's closed when an
+// AfterFunc-armed timer fires. Moving the deadline replaces the channel
+// instead of reusing it, so a read already parked on the old one isn't
+// woken early by the next set call.
+type deadline struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+}
+
+func newDeadline() *deadline {
+	return &deadline{cancel: make(chan struct{})}
+}
+
+// set arms the deadline for t. A zero t disables it.
+func (d *deadline) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.cancel = make(chan struct{})
+
+	if t.IsZero() {
+		return
+	}
+
+	cancel := d.cancel
+	until := time.Until(t)
+	if until <= 0 {
+		close(cancel)
+		return
+	}
+	d.timer = time.AfterFunc(until, func() { close(cancel) })
+}
+
+// c returns the channel that closes when the current deadline fires. It's
+// stable across calls until the deadline is next moved via set.
+func (d *deadline) c() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cancel
+}
+
+// deadlineReader wraps r so Read respects ctx and an independently
+// settable deadline, racing the underlying (possibly blocking) Read
+// against both in a background goroutine. There's no way to interrupt a
+// Read already in flight, so if the race is lost the goroutine's eventual
+// result is simply discarded.
+type deadlineReader struct {
+	r   io.Reader
+	ctx context.Context
+	dl  *deadline
+}
+
+type deadlineReadResult struct {
+	n   int
+	err error
+}
+
+func (r *deadlineReader) Read(p []byte) (int, error) {
+	done := make(chan deadlineReadResult, 1)
+	go func() {
+		n, err := r.r.Read(p)
+		done <- deadlineReadResult{n, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.n, res.err
+	case <-r.ctx.Done():
+		return 0, r.ctx.Err()
+	case <-r.dl.c():
+		return 0, NewTimeoutError("stream read deadline exceeded", 0)
+	}
+}
+
+// ReadLineWithLimit reads a single '\n'-terminated line from r, returning
+// an error once the accumulated line exceeds maxLineSize instead of
+// growing the buffer without bound (bufio.Scanner's fixed 64KB limit is
+// what this replaces). On a non-limit error (including io.EOF), the
+// partial line read so far is returned alongside it, matching
+// bufio.Reader.ReadBytes's own contract.
+func ReadLineWithLimit(r *bufio.Reader, maxLineSize int) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > maxLineSize {
+			return string(line), fmt.Errorf("SSE line exceeds MaxLineSize of %d bytes", maxLineSize)
+		}
+		if err == nil {
+			return string(line), nil
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return string(line), err
+	}
+}
+
+// dispatchChunk sends result on ch per strategy: BackpressureBlock waits
+// for room, BackpressureDropOldest discards the oldest buffered value (if
+// any) to make room instead of blocking the stream-reading goroutine.
+func dispatchChunk(ch chan StreamResult, result StreamResult, strategy BackpressureStrategy) {
+	if strategy != BackpressureDropOldest {
+		ch <- result
+		return
+	}
+
+	select {
+	case ch <- result:
+		return
+	default:
+	}
+
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- result
+}
@@ -0,0 +1,365 @@
+// Package toolbox ships ready-to-use filesystem tools (dir_tree, read_file,
+// write_file, modify_file) that can be registered on a geminisdk.ToolRegistry
+// or an Agent's Tools registry. Every tool is sandboxed to a root directory
+// chosen at construction time; paths that escape the root via ".." or an
+// absolute path are rejected.
+package toolbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	geminisdk "github.com/OEvortex/geminicli-sdk/go"
+)
+
+// Options configures a Toolbox.
+type Options struct {
+	// AllowWrite enables the write_file and modify_file tools. dir_tree and
+	// read_file are always available regardless of this setting.
+	AllowWrite bool
+}
+
+// Toolbox is a sandboxed set of filesystem tools rooted at a directory.
+type Toolbox struct {
+	root       string
+	allowWrite bool
+}
+
+// New creates a Toolbox sandboxed to root.
+func New(root string, opts Options) *Toolbox {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	return &Toolbox{root: filepath.Clean(abs), allowWrite: opts.AllowWrite}
+}
+
+// resolve maps a user-supplied relative path to an absolute path inside the
+// sandbox root, rejecting absolute paths and any traversal that escapes it.
+func (t *Toolbox) resolve(relative string) (string, error) {
+	if filepath.IsAbs(relative) {
+		return "", fmt.Errorf("path %q must be relative to the sandbox root", relative)
+	}
+
+	joined := filepath.Clean(filepath.Join(t.root, relative))
+	if joined != t.root && !strings.HasPrefix(joined, t.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the sandbox root", relative)
+	}
+
+	return joined, nil
+}
+
+// ToolDefinition pairs a Tool with its ToolHandler, ready to register on a
+// ToolRegistry.
+type ToolDefinition struct {
+	Tool    geminisdk.Tool
+	Handler geminisdk.ToolHandler
+}
+
+// All returns every tool the toolbox offers. write_file and modify_file are
+// included only when the toolbox was created with Options.AllowWrite true.
+func (t *Toolbox) All() []ToolDefinition {
+	defs := []ToolDefinition{
+		{Tool: t.dirTreeTool(), Handler: t.dirTreeHandler()},
+		{Tool: t.readFileTool(), Handler: t.readFileHandler()},
+	}
+
+	if t.allowWrite {
+		defs = append(defs,
+			ToolDefinition{Tool: t.writeFileTool(), Handler: t.writeFileHandler()},
+			ToolDefinition{Tool: t.modifyFileTool(), Handler: t.modifyFileHandler()},
+		)
+	}
+
+	return defs
+}
+
+// Register adds every tool from All() to registry.
+func (t *Toolbox) Register(registry *geminisdk.ToolRegistry) {
+	for _, def := range t.All() {
+		registry.Register(def.Tool, def.Handler)
+	}
+}
+
+const maxDirTreeDepth = 5
+
+type dirNode struct {
+	Name     string     `json:"name"`
+	Type     string     `json:"type"`
+	Children []*dirNode `json:"children,omitempty"`
+}
+
+func (t *Toolbox) dirTreeTool() geminisdk.Tool {
+	return geminisdk.DefineTool(
+		"dir_tree",
+		"List files and directories under a relative path as a JSON tree, up to a maximum depth of 5.",
+		geminisdk.NewToolParameters().
+			AddString("relative_path", "Directory to list, relative to the sandbox root. Empty string lists the root itself.").
+			AddInteger("depth", "Maximum depth to recurse, capped at 5."),
+	)
+}
+
+func (t *Toolbox) dirTreeHandler() geminisdk.ToolHandler {
+	return func(ctx context.Context, invocation geminisdk.ToolInvocation) geminisdk.ToolResult {
+		relative, _ := invocation.Arguments["relative_path"].(string)
+
+		depth := maxDirTreeDepth
+		if v, ok := invocation.Arguments["depth"].(float64); ok && int(v) > 0 && int(v) < depth {
+			depth = int(v)
+		}
+
+		start, err := t.resolve(relative)
+		if err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		info, err := os.Stat(start)
+		if err != nil {
+			return geminisdk.FailureResult(fmt.Sprintf("cannot stat %q: %v", relative, err))
+		}
+
+		node, err := buildDirTree(start, info, depth)
+		if err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		data, err := json.Marshal(node)
+		if err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		return geminisdk.SuccessResult(string(data))
+	}
+}
+
+func buildDirTree(path string, info os.FileInfo, depth int) (*dirNode, error) {
+	node := &dirNode{Name: info.Name()}
+	if !info.IsDir() {
+		node.Type = "file"
+		return node, nil
+	}
+	node.Type = "dir"
+
+	if depth <= 0 {
+		return node, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		childInfo, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		child, err := buildDirTree(filepath.Join(path, entry.Name()), childInfo, depth-1)
+		if err != nil {
+			continue
+		}
+		node.Children = append(node.Children, child)
+	}
+
+	return node, nil
+}
+
+func (t *Toolbox) readFileTool() geminisdk.Tool {
+	return geminisdk.DefineTool(
+		"read_file",
+		"Read a file from the sandbox, optionally restricted to a 1-indexed inclusive line range.",
+		geminisdk.NewToolParameters().
+			AddString("path", "File to read, relative to the sandbox root.").
+			AddInteger("start_line", "First line to include (1-indexed). Omit to start at the beginning.").
+			AddInteger("end_line", "Last line to include (1-indexed). Omit to read to the end.").
+			Required("path"),
+	)
+}
+
+func (t *Toolbox) readFileHandler() geminisdk.ToolHandler {
+	return func(ctx context.Context, invocation geminisdk.ToolInvocation) geminisdk.ToolResult {
+		relative, _ := invocation.Arguments["path"].(string)
+
+		path, err := t.resolve(relative)
+		if err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return geminisdk.FailureResult(fmt.Sprintf("cannot read %q: %v", relative, err))
+		}
+
+		startLine := 0
+		endLine := 0
+		if v, ok := invocation.Arguments["start_line"].(float64); ok {
+			startLine = int(v)
+		}
+		if v, ok := invocation.Arguments["end_line"].(float64); ok {
+			endLine = int(v)
+		}
+
+		if startLine <= 0 && endLine <= 0 {
+			return geminisdk.SuccessResult(string(data))
+		}
+
+		lines := strings.Split(string(data), "\n")
+		if startLine <= 0 {
+			startLine = 1
+		}
+		if startLine > len(lines) {
+			return geminisdk.SuccessResult("")
+		}
+		if endLine <= 0 || endLine > len(lines) {
+			endLine = len(lines)
+		}
+
+		return geminisdk.SuccessResult(strings.Join(lines[startLine-1:endLine], "\n"))
+	}
+}
+
+func (t *Toolbox) writeFileTool() geminisdk.Tool {
+	return geminisdk.DefineTool(
+		"write_file",
+		"Create or overwrite a file in the sandbox with the given content, creating parent directories as needed.",
+		geminisdk.NewToolParameters().
+			AddString("path", "File to write, relative to the sandbox root.").
+			AddString("content", "Full content to write to the file.").
+			Required("path", "content"),
+	)
+}
+
+func (t *Toolbox) writeFileHandler() geminisdk.ToolHandler {
+	return func(ctx context.Context, invocation geminisdk.ToolInvocation) geminisdk.ToolResult {
+		if !t.allowWrite {
+			return geminisdk.FailureResult("write_file is disabled: toolbox was created with AllowWrite: false")
+		}
+
+		relative, _ := invocation.Arguments["path"].(string)
+		content, _ := invocation.Arguments["content"].(string)
+
+		path, err := t.resolve(relative)
+		if err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return geminisdk.FailureResult(fmt.Sprintf("cannot write %q: %v", relative, err))
+		}
+
+		return geminisdk.SuccessResult(fmt.Sprintf("Wrote %d bytes to %s", len(content), relative))
+	}
+}
+
+// fileEdit is a single old_string/new_string replacement applied by
+// modify_file. old_string must match exactly once in the file.
+type fileEdit struct {
+	OldString string `json:"old_string"`
+	NewString string `json:"new_string"`
+}
+
+func (t *Toolbox) modifyFileTool() geminisdk.Tool {
+	params, _ := json.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "File to modify, relative to the sandbox root.",
+			},
+			"edits": map[string]interface{}{
+				"type":        "array",
+				"description": "Ordered list of {old_string, new_string} replacements. Each old_string must match exactly once in the file at the time it is applied.",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"old_string": map[string]interface{}{"type": "string"},
+						"new_string": map[string]interface{}{"type": "string"},
+					},
+					"required": []string{"old_string", "new_string"},
+				},
+			},
+		},
+		"required": []string{"path", "edits"},
+	})
+
+	return geminisdk.Tool{
+		Name:        "modify_file",
+		Description: "Apply a sequence of exact-match string replacements to a file in the sandbox.",
+		Parameters:  params,
+	}
+}
+
+func (t *Toolbox) modifyFileHandler() geminisdk.ToolHandler {
+	return func(ctx context.Context, invocation geminisdk.ToolInvocation) geminisdk.ToolResult {
+		if !t.allowWrite {
+			return geminisdk.FailureResult("modify_file is disabled: toolbox was created with AllowWrite: false")
+		}
+
+		relative, _ := invocation.Arguments["path"].(string)
+
+		path, err := t.resolve(relative)
+		if err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		rawEdits, ok := invocation.Arguments["edits"]
+		if !ok {
+			return geminisdk.FailureResult("modify_file requires an \"edits\" argument")
+		}
+
+		edits, err := parseEdits(rawEdits)
+		if err != nil {
+			return geminisdk.FailureResult(err.Error())
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return geminisdk.FailureResult(fmt.Sprintf("cannot read %q: %v", relative, err))
+		}
+		content := string(data)
+
+		for i, edit := range edits {
+			switch count := strings.Count(content, edit.OldString); count {
+			case 0:
+				return geminisdk.FailureResult(fmt.Sprintf("edit %d: old_string not found in %s", i, relative))
+			case 1:
+				content = strings.Replace(content, edit.OldString, edit.NewString, 1)
+			default:
+				return geminisdk.FailureResult(fmt.Sprintf("edit %d: old_string matches %d times in %s, must be unique", i, count, relative))
+			}
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return geminisdk.FailureResult(fmt.Sprintf("cannot write %q: %v", relative, err))
+		}
+
+		return geminisdk.SuccessResult(fmt.Sprintf("Applied %d edit(s) to %s", len(edits), relative))
+	}
+}
+
+func parseEdits(raw interface{}) ([]fileEdit, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []fileEdit
+	if err := json.Unmarshal(data, &edits); err != nil {
+		return nil, fmt.Errorf("invalid edits: %v", err)
+	}
+	if len(edits) == 0 {
+		return nil, fmt.Errorf("edits must contain at least one entry")
+	}
+
+	return edits, nil
+}
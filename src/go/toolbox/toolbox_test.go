@@ -0,0 +1,143 @@
+package toolbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	geminisdk "github.com/OEvortex/geminicli-sdk/go"
+)
+
+func newTestToolbox(t *testing.T) (*Toolbox, string) {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "hello.txt"), []byte("hello\nworld\n"), 0644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+	return New(root, Options{AllowWrite: true}), root
+}
+
+func invoke(handler geminisdk.ToolHandler, args map[string]interface{}) geminisdk.ToolResult {
+	return handler(context.Background(), geminisdk.ToolInvocation{Arguments: args})
+}
+
+func TestResolveRejectsPathEscapes(t *testing.T) {
+	tb, _ := newTestToolbox(t)
+
+	escapes := []string{
+		"../../etc/passwd",
+		"../outside.txt",
+		"a/../../outside.txt",
+	}
+	for _, p := range escapes {
+		if _, err := tb.resolve(p); err == nil {
+			t.Errorf("resolve(%q) = nil error, want rejection", p)
+		}
+	}
+}
+
+func TestResolveRejectsAbsolutePaths(t *testing.T) {
+	tb, _ := newTestToolbox(t)
+
+	if _, err := tb.resolve("/etc/passwd"); err == nil {
+		t.Errorf("resolve(%q) = nil error, want rejection", "/etc/passwd")
+	}
+}
+
+func TestReadFileRejectsEscape(t *testing.T) {
+	tb, _ := newTestToolbox(t)
+
+	result := invoke(tb.readFileHandler(), map[string]interface{}{"path": "../../etc/passwd"})
+	if result.ResultType != geminisdk.ToolResultFailure {
+		t.Errorf("read_file(escape) result type = %v, want failure", result.ResultType)
+	}
+}
+
+func TestWriteFileRejectsEscape(t *testing.T) {
+	tb, _ := newTestToolbox(t)
+
+	result := invoke(tb.writeFileHandler(), map[string]interface{}{
+		"path":    "/etc/passwd",
+		"content": "pwned",
+	})
+	if result.ResultType != geminisdk.ToolResultFailure {
+		t.Errorf("write_file(escape) result type = %v, want failure", result.ResultType)
+	}
+}
+
+func TestModifyFileRejectsEscape(t *testing.T) {
+	tb, _ := newTestToolbox(t)
+
+	result := invoke(tb.modifyFileHandler(), map[string]interface{}{
+		"path": "../../etc/passwd",
+		"edits": []interface{}{
+			map[string]interface{}{"old_string": "root", "new_string": "pwned"},
+		},
+	})
+	if result.ResultType != geminisdk.ToolResultFailure {
+		t.Errorf("modify_file(escape) result type = %v, want failure", result.ResultType)
+	}
+}
+
+func TestDirTreeRejectsEscape(t *testing.T) {
+	tb, _ := newTestToolbox(t)
+
+	result := invoke(tb.dirTreeHandler(), map[string]interface{}{"relative_path": "../.."})
+	if result.ResultType != geminisdk.ToolResultFailure {
+		t.Errorf("dir_tree(escape) result type = %v, want failure", result.ResultType)
+	}
+}
+
+func TestModifyFileRoundTrip(t *testing.T) {
+	tb, root := newTestToolbox(t)
+
+	result := invoke(tb.modifyFileHandler(), map[string]interface{}{
+		"path": "hello.txt",
+		"edits": []interface{}{
+			map[string]interface{}{"old_string": "world", "new_string": "gophers"},
+		},
+	})
+	if result.ResultType != geminisdk.ToolResultSuccess {
+		t.Fatalf("modify_file round-trip result type = %v, want success: %s", result.ResultType, result.TextResultForLLM)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "hello.txt"))
+	if err != nil {
+		t.Fatalf("read back: %v", err)
+	}
+	if got, want := string(data), "hello\ngophers\n"; got != want {
+		t.Errorf("file content = %q, want %q", got, want)
+	}
+}
+
+func TestModifyFileRejectsNoMatch(t *testing.T) {
+	tb, _ := newTestToolbox(t)
+
+	result := invoke(tb.modifyFileHandler(), map[string]interface{}{
+		"path": "hello.txt",
+		"edits": []interface{}{
+			map[string]interface{}{"old_string": "does-not-exist", "new_string": "x"},
+		},
+	})
+	if result.ResultType != geminisdk.ToolResultFailure {
+		t.Errorf("modify_file(no match) result type = %v, want failure", result.ResultType)
+	}
+}
+
+func TestModifyFileRejectsAmbiguousMatch(t *testing.T) {
+	tb, root := newTestToolbox(t)
+	if err := os.WriteFile(filepath.Join(root, "dup.txt"), []byte("a\na\n"), 0644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+
+	result := invoke(tb.modifyFileHandler(), map[string]interface{}{
+		"path": "dup.txt",
+		"edits": []interface{}{
+			map[string]interface{}{"old_string": "a", "new_string": "b"},
+		},
+	})
+	if result.ResultType != geminisdk.ToolResultFailure {
+		t.Errorf("modify_file(ambiguous match) result type = %v, want failure", result.ResultType)
+	}
+}
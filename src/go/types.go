@@ -2,6 +2,7 @@ package geminisdk
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -50,9 +51,45 @@ const (
 	EventAssistantReasoning     EventType = "assistant.reasoning"
 	EventAssistantReasoningDelta EventType = "assistant.reasoning_delta"
 	EventToolCall               EventType = "tool.call"
+	EventToolCallPending        EventType = "tool.call_pending"
+	EventToolCallsPending       EventType = "tool.calls_pending"
 	EventToolResult             EventType = "tool.result"
+	EventSessionForked          EventType = "session.forked"
+	// EventSessionQuotaExceeded is a more specific EventSessionError for a
+	// 429/ErrQuotaExceeded or ErrRateLimited failure, so a multi-profile
+	// Client can tell a quota exhaustion apart from an auth failure and
+	// rotate to the next configured profile; the failed call's error is
+	// still returned to its caller.
+	EventSessionQuotaExceeded EventType = "session.quota_exceeded"
+	// EventSessionAuthFailed is a more specific EventSessionError for an
+	// authentication failure (expired/invalid/missing credentials).
+	EventSessionAuthFailed EventType = "session.auth_failed"
+	// EventUserMessage fires when a user message is appended to history,
+	// carrying its "content". FileEventLogStore relies on this (alongside
+	// EventAssistantMessage and EventToolResult) to replay a session's
+	// message history from its logged events in Resume/Fork.
+	EventUserMessage EventType = "user.message"
+	// EventSessionResumed fires once FileEventLogStore.Resume finishes
+	// rehydrating a Session from its event log, so subscribers (e.g. a CLI
+	// printing "resumed conversation") can distinguish it from a freshly
+	// created one.
+	EventSessionResumed EventType = "session.resumed"
 )
 
+// isSessionErrorEvent reports whether t is EventSessionError or one of its
+// more specific variants (EventSessionQuotaExceeded, EventSessionAuthFailed).
+// Callers that used to switch on EventSessionError alone (auto-persist
+// triggers, SendAndWait) should use this instead so they still fire once
+// the error is more precisely classified.
+func isSessionErrorEvent(t EventType) bool {
+	switch t {
+	case EventSessionError, EventSessionQuotaExceeded, EventSessionAuthFailed:
+		return true
+	default:
+		return false
+	}
+}
+
 // OAuth constants
 const (
 	GeminiOAuthRedirectURI    = "http://localhost:45289"
@@ -102,6 +139,17 @@ type ModelSupports struct {
 	Thinking bool `json:"thinking"`
 }
 
+// ModelCapability names one of ModelSupports' flags, for callers that want
+// to check it by name (e.g. ModelRegistry.SupportsCapability) rather than
+// switching on the ModelSupports struct directly.
+type ModelCapability string
+
+const (
+	CapabilityVision   ModelCapability = "vision"
+	CapabilityTools    ModelCapability = "tools"
+	CapabilityThinking ModelCapability = "thinking"
+)
+
 // ModelVisionLimits represents vision limits
 type ModelVisionLimits struct {
 	SupportedMediaTypes []string `json:"supported_media_types,omitempty"`
@@ -135,6 +183,28 @@ type ContentPart struct {
 	ImageURL      string `json:"image_url,omitempty"`
 	ImageData     []byte `json:"image_data,omitempty"`
 	ImageMimeType string `json:"image_mime_type,omitempty"`
+
+	// Data and MimeType carry an arbitrary inline blob — audio, video, a
+	// PDF, or any other document — generalizing the image-only fields
+	// above. Blobs at or under Backend's inline size threshold are sent
+	// as inlineData; larger ones must be uploaded first via
+	// Backend.UploadFile and referenced through FileURI instead.
+	Data     []byte `json:"data,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	// FileURI references a file already uploaded via Backend.UploadFile,
+	// sent as fileData instead of inlineData. MimeType still applies.
+	FileURI string `json:"file_uri,omitempty"`
+}
+
+// FileRef references a file uploaded to the Gemini Files API via
+// Backend.UploadFile. Set it on a ContentPart's FileURI/MimeType fields to
+// include it in a message without re-uploading the bytes.
+type FileRef struct {
+	URI       string `json:"uri"`
+	Name      string `json:"name"`
+	MimeType  string `json:"mime_type"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+	State     string `json:"state,omitempty"`
 }
 
 // Message represents a conversation message
@@ -208,6 +278,40 @@ type GenerationConfig struct {
 	TopP            float64  `json:"top_p,omitempty"`
 	TopK            int      `json:"top_k,omitempty"`
 	StopSequences   []string `json:"stop_sequences,omitempty"`
+	// ResponseMimeType requests constrained decoding, e.g.
+	// "application/json" or "text/x.enum".
+	ResponseMimeType string `json:"response_mime_type,omitempty"`
+	// ResponseSchema shapes the constrained output when ResponseMimeType
+	// is "application/json". LLMChunk.UnmarshalStructured validates a
+	// structured response against it before unmarshaling.
+	ResponseSchema *Schema `json:"response_schema,omitempty"`
+}
+
+// SchemaType enumerates the value types Schema.Type accepts, matching
+// Gemini's constrained-decoding OpenAPI-subset schema.
+type SchemaType string
+
+const (
+	SchemaTypeString  SchemaType = "STRING"
+	SchemaTypeNumber  SchemaType = "NUMBER"
+	SchemaTypeInteger SchemaType = "INTEGER"
+	SchemaTypeBoolean SchemaType = "BOOLEAN"
+	SchemaTypeArray   SchemaType = "ARRAY"
+	SchemaTypeObject  SchemaType = "OBJECT"
+)
+
+// Schema describes the shape of a constrained-decoding response. It mirrors
+// the OpenAPI-subset of JSON Schema that Gemini's responseSchema accepts,
+// not the full JSON Schema specification.
+type Schema struct {
+	Type             SchemaType         `json:"type,omitempty"`
+	Properties       map[string]*Schema `json:"properties,omitempty"`
+	Required         []string           `json:"required,omitempty"`
+	Items            *Schema            `json:"items,omitempty"`
+	Enum             []string           `json:"enum,omitempty"`
+	Nullable         bool               `json:"nullable,omitempty"`
+	Format           string             `json:"format,omitempty"`
+	PropertyOrdering []string           `json:"propertyOrdering,omitempty"`
 }
 
 // ThinkingConfig represents thinking configuration
@@ -237,6 +341,74 @@ type LLMChunk struct {
 	ToolCalls        []ToolCall `json:"tool_calls,omitempty"`
 	Usage            *LLMUsage  `json:"usage,omitempty"`
 	FinishReason     string     `json:"finish_reason,omitempty"`
+
+	// StructuredContent holds Content re-parsed as JSON when the request set
+	// GenerationConfig.ResponseMimeType to "application/json", so callers
+	// that just want the raw document don't have to unmarshal Content
+	// themselves. It is nil when Content isn't valid JSON.
+	StructuredContent json.RawMessage `json:"structured_content,omitempty"`
+
+	// responseSchema is the schema the originating request passed via
+	// GenerationConfig.ResponseSchema, if any. It isn't part of the wire
+	// format; UnmarshalStructured uses it to validate Content before
+	// unmarshaling.
+	responseSchema *Schema
+}
+
+// UnmarshalStructured unmarshals Content (expected to be JSON, as produced
+// by a request with GenerationConfig.ResponseMimeType set to
+// "application/json") into v. If the request specified a ResponseSchema,
+// Content is validated against it first, returning a *SchemaValidationError
+// naming the offending JSON path when the model's output doesn't conform.
+func (c *LLMChunk) UnmarshalStructured(v any) error {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(c.Content), &doc); err != nil {
+		return fmt.Errorf("structured content is not valid JSON: %w", err)
+	}
+
+	if c.responseSchema != nil {
+		if err := validateSchema(c.responseSchema, doc, "$"); err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal([]byte(c.Content), v)
+}
+
+// EmbedTaskType hints how an embedding will be used, letting the model
+// optimize the vector for that downstream task.
+type EmbedTaskType string
+
+const (
+	EmbedTaskRetrievalQuery     EmbedTaskType = "RETRIEVAL_QUERY"
+	EmbedTaskRetrievalDocument  EmbedTaskType = "RETRIEVAL_DOCUMENT"
+	EmbedTaskSemanticSimilarity EmbedTaskType = "SEMANTIC_SIMILARITY"
+	EmbedTaskClassification     EmbedTaskType = "CLASSIFICATION"
+	EmbedTaskClustering         EmbedTaskType = "CLUSTERING"
+)
+
+// EmbedInput is one piece of text to embed
+type EmbedInput struct {
+	Text     string        `json:"text"`
+	Title    string        `json:"title,omitempty"`
+	TaskType EmbedTaskType `json:"task_type,omitempty"`
+}
+
+// EmbedOptions configures an embedding request
+type EmbedOptions struct {
+	// OutputDimensionality truncates each returned vector to this many
+	// dimensions, if set.
+	OutputDimensionality int `json:"output_dimensionality,omitempty"`
+	// AutoTruncate truncates inputs longer than the model's token limit
+	// instead of returning an error.
+	AutoTruncate bool `json:"auto_truncate,omitempty"`
+}
+
+// EmbedResponse represents the result of an embedding request: one vector
+// per input, in the same order they were given
+type EmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+	Usage      *LLMUsage   `json:"usage,omitempty"`
 }
 
 // SessionConfig represents session configuration
@@ -248,6 +420,8 @@ type SessionConfig struct {
 	GenerationConfig *GenerationConfig `json:"generation_config,omitempty"`
 	ThinkingConfig   *ThinkingConfig  `json:"thinking_config,omitempty"`
 	Streaming        bool             `json:"streaming"`
+	ApprovalPolicy   ApprovalPolicy   `json:"-"`
+	ManualToolDispatch bool           `json:"manual_tool_dispatch,omitempty"`
 }
 
 // SessionMetadata represents session metadata
@@ -261,13 +435,26 @@ type SessionMetadata struct {
 
 // ClientOptions represents client configuration options
 type ClientOptions struct {
-	OAuthPath    string        `json:"oauth_path,omitempty"`
-	ClientID     string        `json:"client_id,omitempty"`
-	ClientSecret string        `json:"client_secret,omitempty"`
-	BaseURL      string        `json:"base_url,omitempty"`
-	Timeout      float64       `json:"timeout,omitempty"`
-	LogLevel     LogLevel      `json:"log_level,omitempty"`
-	AutoRefresh  bool          `json:"auto_refresh"`
+	OAuthPath        string           `json:"oauth_path,omitempty"`
+	ClientID         string           `json:"client_id,omitempty"`
+	ClientSecret     string           `json:"client_secret,omitempty"`
+	BaseURL          string           `json:"base_url,omitempty"`
+	Timeout          float64          `json:"timeout,omitempty"`
+	LogLevel         LogLevel         `json:"log_level,omitempty"`
+	AutoRefresh      bool             `json:"auto_refresh"`
+	CredentialStore  CredentialStore  `json:"-"`
+	SessionStore     SessionStore     `json:"-"`
+	AutoPersist      bool             `json:"auto_persist"`
+	CredentialSource CredentialSource `json:"-"`
+	// Profile is the initially active account label when Profiles is set.
+	// Ignored if CredentialStore is also set — an explicit CredentialStore
+	// always wins.
+	Profile string `json:"profile,omitempty"`
+	// Profiles configures a ProfileCredentialStore rotating through these
+	// account labels (personal, workspace, a service account, ...) in
+	// order: a 429/quota error from one account promotes the next. Ignored
+	// if CredentialStore is also set.
+	Profiles []string `json:"profiles,omitempty"`
 }
 
 // SessionEvent represents an event from a session
@@ -349,6 +536,24 @@ func GetGeminiCLIModels() map[string]GeminiModelInfo {
 	}
 }
 
+// GetGeminiCLIEmbeddingModels returns available Gemini embedding models
+func GetGeminiCLIEmbeddingModels() map[string]GeminiModelInfo {
+	return map[string]GeminiModelInfo{
+		"gemini-embedding-001": {
+			ID:            "gemini-embedding-001",
+			Name:          "Gemini Embedding 001",
+			ContextWindow: 2048,
+			MaxOutput:     3072,
+		},
+		"text-embedding-004": {
+			ID:            "text-embedding-004",
+			Name:          "Text Embedding 004",
+			ContextWindow: 2048,
+			MaxOutput:     768,
+		},
+	}
+}
+
 // GetUserAgent returns the user agent string
 func GetUserAgent() string {
 	return "GeminiSDK-Go/0.1.0 (" + runtime.GOOS + "; " + runtime.GOARCH + ")"
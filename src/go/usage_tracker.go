@@ -0,0 +1,188 @@
+package geminisdk
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// usageLedgerFilename is where UsageTracker appends its rolling ledger,
+// inside GeminiDir (~/.gemini), alongside oauth_creds.json and models.json.
+const usageLedgerFilename = "usage.jsonl"
+
+// UsageTracker maintains a running LLMUsage and cumulative USD cost per
+// session, priced from GetGeminiCLIModels'/GetGeminiCLIEmbeddingModels'
+// InputPrice/OutputPrice (dollars per token; previously tracked but never
+// consumed anywhere), and appends each update to a JSONL ledger so usage
+// survives process restarts. Attach a session to start tracking it; a
+// single tracker can be attached to any number of sessions.
+type UsageTracker struct {
+	ledgerPath string
+
+	mu       sync.Mutex
+	budget   float64
+	sessions map[string]*sessionUsage
+}
+
+type sessionUsage struct {
+	model   string
+	usage   LLMUsage
+	costUSD float64
+}
+
+// UsageLedgerEntry is one line of UsageTracker's ledger file: a running
+// total as of the event that produced it, not a per-call delta.
+type UsageLedgerEntry struct {
+	SessionID string    `json:"session_id"`
+	Model     string    `json:"model"`
+	Usage     LLMUsage  `json:"usage"`
+	CostUSD   float64   `json:"cost_usd"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewUsageTracker creates a tracker persisting to customLedgerPath (empty
+// resolves to ~/.gemini/usage.jsonl).
+func NewUsageTracker(customLedgerPath string) *UsageTracker {
+	path := customLedgerPath
+	if path == "" {
+		homeDir, _ := os.UserHomeDir()
+		path = filepath.Join(homeDir, GeminiDir, usageLedgerFilename)
+	}
+	return &UsageTracker{
+		ledgerPath: path,
+		sessions:   make(map[string]*sessionUsage),
+	}
+}
+
+// Budget sets a USD ceiling that applies to each attached session on its
+// own: costs of different sessions are not added together. Every usage
+// update that leaves a session's running CostUSD above limit emits
+// EventSessionError instead of letting it pass silently. Zero (the
+// default) disables the guard.
+func (t *UsageTracker) Budget(limit float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budget = limit
+}
+
+// Attach starts tracking session: every EventAssistantMessage updates its
+// running LLMUsage and CostUSD from the response's usage and the session's
+// model pricing, appends an entry to the ledger, and — if Budget is set —
+// emits EventSessionError once the session's cumulative cost exceeds it.
+// Returns session for chaining, e.g. tracker.Attach(session).SendAndWait(...).
+func (t *UsageTracker) Attach(session *Session) *Session {
+	sessionID := session.SessionID()
+
+	t.mu.Lock()
+	t.sessions[sessionID] = &sessionUsage{model: session.Model()}
+	t.mu.Unlock()
+
+	session.On(func(event SessionEvent) {
+		if event.EventType != EventAssistantMessage {
+			return
+		}
+		usage, _ := event.Data["usage"].(*LLMUsage)
+		if usage == nil {
+			return
+		}
+
+		entry, overBudget := t.record(sessionID, usage)
+		_ = t.appendLedger(sessionID, entry)
+		if overBudget {
+			session.emit(EventSessionError, map[string]interface{}{
+				"error": "usage budget exceeded",
+			})
+		}
+	})
+
+	return session
+}
+
+func (t *UsageTracker) record(sessionID string, usage *LLMUsage) (sessionUsage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.sessions[sessionID]
+	if !ok {
+		entry = &sessionUsage{}
+		t.sessions[sessionID] = entry
+	}
+	entry.usage.PromptTokens += usage.PromptTokens
+	entry.usage.CompletionTokens += usage.CompletionTokens
+	entry.usage.TotalTokens += usage.TotalTokens
+	entry.costUSD += estimateCostUSD(entry.model, usage)
+
+	overBudget := t.budget > 0 && entry.costUSD > t.budget
+	return *entry, overBudget
+}
+
+func (t *UsageTracker) appendLedger(sessionID string, entry sessionUsage) error {
+	line, err := json.Marshal(UsageLedgerEntry{
+		SessionID: sessionID,
+		Model:     entry.model,
+		Usage:     entry.usage,
+		CostUSD:   entry.costUSD,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.ledgerPath), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(t.ledgerPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Usage returns sessionID's running LLMUsage and cumulative CostUSD, or
+// zero values if it hasn't seen any usage for that session yet.
+func (t *UsageTracker) Usage(sessionID string) (LLMUsage, float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.sessions[sessionID]
+	if !ok {
+		return LLMUsage{}, 0
+	}
+	return entry.usage, entry.costUSD
+}
+
+// estimateCostUSD prices usage against model's GeminiModelInfo entry,
+// returning 0 for a model absent from both catalogs rather than erroring —
+// cost tracking is best-effort and shouldn't block the session it watches.
+func estimateCostUSD(model string, usage *LLMUsage) float64 {
+	info, ok := GetGeminiCLIModels()[model]
+	if !ok {
+		info, ok = GetGeminiCLIEmbeddingModels()[model]
+		if !ok {
+			return 0
+		}
+	}
+	return float64(usage.PromptTokens)*info.InputPrice + float64(usage.CompletionTokens)*info.OutputPrice
+}
+
+// approxBytesPerToken approximates token density for English prose, the
+// same rough ~4-bytes-per-token rule of thumb commonly used for a quick
+// estimate without running the real BPE tokenizer. It's an approximation,
+// not an exact count — expect roughly ±10% accuracy against the model's
+// actual tokenizer.
+const approxBytesPerToken = 4
+
+func approxTokenCount(s string) int64 {
+	if len(s) == 0 {
+		return 0
+	}
+	if tokens := int64(len(s)) / approxBytesPerToken; tokens > 0 {
+		return tokens
+	}
+	return 1
+}